@@ -0,0 +1,86 @@
+package stripetotrello
+
+import (
+	"context"
+	"sync"
+
+	stripe "github.com/stripe/stripe-go/v76"
+)
+
+// HandleBatch dispatches the events with HandleContext and returns their
+// errors aligned with events. With WithMaxConcurrency up to that many events
+// run at once, otherwise they run one after the other in order; see
+// WithPartitionKey to keep the order of related events. An event
+// whose ID already appeared earlier in the batch is skipped like a duplicate
+// caught by the Deduper, which still applies. Once ctx is done the events not
+// started yet fail with its error.
+func (st *Client) HandleBatch(ctx context.Context, events []*stripe.Event) []error {
+	errs := make([]error, len(events))
+
+	seen := make(map[string]struct{}, len(events))
+	pending := make([]int, 0, len(events))
+	for i, event := range events {
+		if event.ID != "" {
+			if _, ok := seen[event.ID]; ok {
+				continue
+			}
+			seen[event.ID] = struct{}{}
+		}
+		pending = append(pending, i)
+	}
+
+	handle := func(i int) {
+		if err := ctx.Err(); err != nil {
+			errs[i] = newError("Client.HandleBatch", []interface{}{events[i]}, err)
+			return
+		}
+		errs[i] = st.HandleContext(ctx, events[i])
+	}
+
+	workers := st.maxConcurrency
+	if workers <= 1 {
+		for _, i := range pending {
+			handle(i)
+		}
+		return errs
+	}
+
+	var wg sync.WaitGroup
+	run := func(indexes <-chan int) {
+		defer wg.Done()
+		for i := range indexes {
+			handle(i)
+		}
+	}
+
+	if st.partitionKey == nil {
+		indexes := make(chan int)
+		for w := 0; w < workers && w < len(pending); w++ {
+			wg.Add(1)
+			go run(indexes)
+		}
+		for _, i := range pending {
+			indexes <- i
+		}
+		close(indexes)
+		wg.Wait()
+		return errs
+	}
+
+	// Every worker gets its own channel so the events of a key stay in order.
+	p := &partitioner{key: st.partitionKey, workers: workers}
+	queues := make([]chan int, workers)
+	for w := range queues {
+		queues[w] = make(chan int, len(pending))
+		wg.Add(1)
+		go run(queues[w])
+	}
+	for _, i := range pending {
+		queues[p.worker(events[i])] <- i
+	}
+	for _, q := range queues {
+		close(q)
+	}
+	wg.Wait()
+	return errs
+}
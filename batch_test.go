@@ -0,0 +1,78 @@
+package stripetotrello
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	stripe "github.com/stripe/stripe-go/v76"
+)
+
+func TestHandleBatch(t *testing.T) {
+	type testCase struct {
+		name string
+		cfgs []func(*Client)
+	}
+
+	tcs := []testCase{
+		{"sequential", nil},
+		{"concurrent", []func(*Client){WithMaxConcurrency(4)}},
+		{"deduper", []func(*Client){WithMaxConcurrency(4), WithDeduper(NewMemoryDeduper(100, 0))}},
+	}
+
+	for _, tc := range tcs {
+		var mu sync.Mutex
+		calls := map[string]int{}
+		client := NewClient(tc.cfgs...)
+		client.AppendHandler("customer.created", func(e *stripe.Event) (EventResponse, error) {
+			mu.Lock()
+			calls[e.ID]++
+			mu.Unlock()
+			if e.ID == "evt_3" {
+				return nil, errors.New("test")
+			}
+			return "ok", nil
+		})
+
+		var events []*stripe.Event
+		for i := 0; i < 10; i++ {
+			events = append(events, &stripe.Event{ID: fmt.Sprintf("evt_%d", i), Type: "customer.created"})
+		}
+		events = append(events, &stripe.Event{ID: "evt_1", Type: "customer.created"})
+
+		errs := client.HandleBatch(context.Background(), events)
+		if len(errs) != len(events) {
+			t.Fatalf("%s: Expected %d errors got %d", tc.name, len(events), len(errs))
+		}
+		for i, err := range errs {
+			if i == 3 && err == nil {
+				t.Errorf("%s: Event should have failed evt_3", tc.name)
+			}
+			if i != 3 && err != nil {
+				t.Errorf("%s: Event should have NOT failed %s - %v", tc.name, events[i].ID, err)
+			}
+		}
+		for i := 0; i < 10; i++ {
+			if n := calls[fmt.Sprintf("evt_%d", i)]; n != 1 {
+				t.Errorf("%s: Expected evt_%d to be handled once got %d", tc.name, i, n)
+			}
+		}
+	}
+}
+
+func TestHandleBatchCancelled(t *testing.T) {
+	client := NewClient()
+	client.AppendHandler("customer.created", func(_ *stripe.Event) (EventResponse, error) {
+		return "ok", nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for _, err := range client.HandleBatch(ctx, []*stripe.Event{{ID: "evt_1", Type: "customer.created"}}) {
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected context.Canceled got %v", err)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package stripetotrello
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+type (
+	// circuitBreaker tracks the consecutive failed dispatches of each event
+	// type. Once open, dispatches fail with ErrCircuitOpen until openDuration
+	// elapsed, then a single probe is let through which closes the breaker on
+	// success or opens it again on failure.
+	circuitBreaker struct {
+		threshold    int
+		openDuration time.Duration
+		clock        Clock
+
+		mu     sync.Mutex
+		states map[string]*circuitState
+	}
+
+	circuitState struct {
+		failures int
+		open     bool
+		openedAt time.Time
+		probing  bool
+	}
+)
+
+func WithCircuitBreaker(failureThreshold int, openDuration time.Duration) func(*Client) {
+	return func(c *Client) {
+		if failureThreshold <= 0 {
+			c.breaker = nil
+			return
+		}
+		c.breaker = &circuitBreaker{
+			threshold:    failureThreshold,
+			openDuration: openDuration,
+			states:       make(map[string]*circuitState),
+		}
+	}
+}
+
+func (cb *circuitBreaker) now() time.Time {
+	if cb.clock == nil {
+		return time.Now()
+	}
+	return cb.clock.Now()
+}
+
+func (cb *circuitBreaker) state(eventType string) *circuitState {
+	s, ok := cb.states[eventType]
+	if !ok {
+		s = &circuitState{}
+		cb.states[eventType] = s
+	}
+	return s
+}
+
+func (cb *circuitBreaker) allow(eventType string) bool {
+	if cb == nil {
+		return true
+	}
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	s := cb.state(eventType)
+	if !s.open {
+		return true
+	}
+	if s.probing || cb.now().Sub(s.openedAt) < cb.openDuration {
+		return false
+	}
+	s.probing = true
+	return true
+}
+
+// release gives the probe back when the dispatch was skipped before running
+// any handler.
+func (cb *circuitBreaker) release(eventType string) {
+	if cb == nil {
+		return
+	}
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	cb.state(eventType).probing = false
+}
+
+func (cb *circuitBreaker) record(eventType string, err error) {
+	if cb == nil {
+		return
+	}
+	if errors.Is(err, ErrNoHandler) {
+		cb.release(eventType)
+		return
+	}
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	s := cb.state(eventType)
+	if err == nil {
+		*s = circuitState{}
+		return
+	}
+
+	s.failures++
+	if s.probing || s.failures >= cb.threshold {
+		s.open, s.openedAt, s.probing = true, cb.now(), false
+	}
+}
+
+// allOpen reports whether every event type the breaker saw is currently
+// open.
+func (cb *circuitBreaker) allOpen() bool {
+	if cb == nil {
+		return false
+	}
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	now := cb.now()
+	for _, s := range cb.states {
+		if !s.open || now.Sub(s.openedAt) >= cb.openDuration {
+			return false
+		}
+	}
+	return len(cb.states) > 0
+}
@@ -0,0 +1,69 @@
+package stripetotrello
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	stripe "github.com/stripe/stripe-go/v76"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)}
+
+	failing := true
+	calls := 0
+	client := NewClient(WithCircuitBreaker(3, time.Minute), WithClock(clock))
+	client.AppendHandler("customer.created", func(_ *stripe.Event) (EventResponse, error) {
+		calls++
+		if failing {
+			return nil, errors.New("trello unavailable")
+		}
+		return "ok", nil
+	})
+	client.AppendHandler("customer.updated", func(_ *stripe.Event) (EventResponse, error) {
+		return "ok", nil
+	})
+
+	event := &stripe.Event{Type: "customer.created"}
+	for i := 0; i < 3; i++ {
+		if err := client.Handle(event); err == nil || errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("Expected failure %d to come from the handler, got %v", i, err)
+		}
+	}
+
+	for _, handle := range []func(*stripe.Event) error{client.Handle, client.HandleParallel} {
+		err := handle(event)
+		if !errors.Is(err, ErrCircuitOpen) {
+			t.Errorf("Expected the open breaker to short-circuit, got %v", err)
+		}
+		if _, ok := err.(StripeEventError); !ok {
+			t.Errorf("Expected a StripeEventError, got %T", err)
+		}
+	}
+	if calls != 3 {
+		t.Errorf("Expected the handler to NOT run while open, got %d calls", calls)
+	}
+	if err := client.Handle(&stripe.Event{Type: "customer.updated"}); err != nil {
+		t.Errorf("Expected other event types to NOT be affected, got %s", err)
+	}
+
+	clock.now = clock.now.Add(time.Minute)
+	if err := client.Handle(event); err == nil || errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("Expected the half-open probe to run, got %v", err)
+	}
+	if err := client.Handle(event); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("Expected a failed probe to open the breaker again, got %v", err)
+	}
+
+	failing = false
+	clock.now = clock.now.Add(time.Minute)
+	for i := 0; i < 2; i++ {
+		if err := client.Handle(event); err != nil {
+			t.Errorf("Expected the breaker to recover, got %s", err)
+		}
+	}
+	if calls != 6 {
+		t.Errorf("Expected 6 handler calls, got %d", calls)
+	}
+}
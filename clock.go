@@ -0,0 +1,27 @@
+package stripetotrello
+
+import (
+	"time"
+)
+
+type (
+	// Clock is the time source used to check the signature tolerance, to
+	// reopen the circuit breaker and to refill the WithRateLimit buckets.
+	Clock interface {
+		Now() time.Time
+	}
+
+	realClock struct{}
+)
+
+func WithClock(clock Clock) func(*Client) {
+	return func(c *Client) {
+		if clock != nil {
+			c.clock = clock
+		}
+	}
+}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
@@ -0,0 +1,47 @@
+package stripetotrello
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/skipper-digital-studio/stripetotrello/testutil"
+	"github.com/stripe/stripe-go/v76/webhook"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (f fakeClock) Now() time.Time {
+	return f.now
+}
+
+func TestClockTolerance(t *testing.T) {
+	signedAt := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	type testCase struct {
+		name       string
+		now        time.Time
+		shouldFail bool
+	}
+
+	tcs := []testCase{
+		{"in tolerance", signedAt.Add(4 * time.Minute), false},
+		{"at the limit", signedAt.Add(5 * time.Minute), false},
+		{"out of tolerance", signedAt.Add(5*time.Minute + time.Second), true},
+	}
+
+	payload := testutil.Payload("customer.created")
+	for _, tc := range tcs {
+		client := NewClient(WithStripeWebhookSecret(testSecret), WithTolerance(5*time.Minute), WithClock(fakeClock{tc.now}))
+
+		_, err := client.Event(payload, signature(testSecret, payload, signedAt))
+		if err != nil && !tc.shouldFail {
+			t.Errorf("Expected %s to NOT fail, got %s", tc.name, err)
+		}
+		if tc.shouldFail && !errors.Is(err, webhook.ErrTooOld) {
+			t.Errorf("Expected %s to fail with webhook.ErrTooOld, got %v", tc.name, err)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package stripetotrello
+
+import (
+	"github.com/skipper-digital-studio/stripetotrello/internal/ratelimit"
+	stripe "github.com/stripe/stripe-go/v76"
+)
+
+// Clone returns a client with the options and registrations of st, then
+// applies cfgs to it, e.g. Clone(WithStripeWebhookSecret(tenantSecret)).
+// Registering or removing handlers on either client does not affect the
+// other. The clone starts with its own lifecycle, queue, circuit breaker,
+// rate limits and WithMaxConcurrencyFor limits, while the Deduper,
+// DeadLetter, Logger, Metrics, Tracer and other pluggable implementations are
+// shared.
+func (st *Client) Clone(cfgs ...func(*Client)) *Client {
+	c := &Client{
+		stripeWebhookSecrets:  append([]string(nil), st.stripeWebhookSecrets...),
+		tolerance:             st.tolerance,
+		ignoreAPIVersion:      st.ignoreAPIVersion,
+		handlerTimeout:        st.handlerTimeout,
+		maxConcurrency:        st.maxConcurrency,
+		deduper:               st.deduper,
+		recoverPanics:         st.recoverPanics,
+		livemodeOnly:          st.livemodeOnly,
+		testmodeOnly:          st.testmodeOnly,
+		logger:                st.logger,
+		metrics:               st.metrics,
+		tracer:                st.tracer,
+		retryAttempts:         st.retryAttempts,
+		retryBackoff:          st.retryBackoff,
+		continueOnError:       st.continueOnError,
+		queueSize:             st.queueSize,
+		deadLetter:            st.deadLetter,
+		insecureSkipVerify:    st.insecureSkipVerify,
+		verifier:              st.verifier,
+		clock:                 st.clock,
+		parallelCancelOnError: st.parallelCancelOnError,
+		signatureHeader:       st.signatureHeader,
+		maxBodyBytes:          st.maxBodyBytes,
+		secretFunc:            st.secretFunc,
+		stripeAPIKey:          st.stripeAPIKey,
+		stripeBackend:         st.stripeBackend,
+		expand:                make(map[string][]string, len(st.expand)),
+		beforeHandler:         append([]BeforeHandlerHook(nil), st.beforeHandler...),
+		afterHandler:          append([]AfterHandlerHook(nil), st.afterHandler...),
+		strictEventTypes:      st.strictEventTypes,
+		maxConcurrencyFor:     copyMap(st.maxConcurrencyFor),
+		rateLimits:            make(map[string]*ratelimit.Bucket, len(st.rateLimits)),
+		dedupeHandlers:        st.dedupeHandlers,
+		decoders:              copyMap(st.decoders),
+		workerPool:            st.workerPool,
+		correlationIDFunc:     st.correlationIDFunc,
+		statusMapper:          st.statusMapper,
+		errorResponder:        st.errorResponder,
+		partitionKey:          st.partitionKey,
+		onDrop:                st.onDrop,
+		scopeFunc:             st.scopeFunc,
+	}
+	for eventType, paths := range st.expand {
+		c.expand[eventType] = append([]string(nil), paths...)
+	}
+	for eventType, b := range st.rateLimits {
+		c.rateLimits[eventType] = ratelimit.New(b.RPS, b.Burst)
+	}
+	if st.breaker != nil {
+		c.breaker = &circuitBreaker{
+			threshold:    st.breaker.threshold,
+			openDuration: st.breaker.openDuration,
+			states:       make(map[string]*circuitState),
+		}
+	}
+
+	st.mu.RLock()
+	c.handlers = make(map[string][]registeredHandler, len(st.handlers))
+	for eventType, list := range st.handlers {
+		c.handlers[eventType] = append([]registeredHandler(nil), list...)
+	}
+	c.successHandler = make(map[string][]StripeSuccessEventHandler, len(st.successHandler))
+	for eventType, list := range st.successHandler {
+		c.successHandler[eventType] = append([]StripeSuccessEventHandler(nil), list...)
+	}
+	c.failureHandler = copyMap(st.failureHandler)
+	c.successWithErrors = copyMap(st.successWithErrors)
+	c.successDetailed = copyMap(st.successDetailed)
+	c.failureDetailed = copyMap(st.failureDetailed)
+	c.defaultSuccess = st.defaultSuccess
+	c.defaultFailure = st.defaultFailure
+	c.middleware = append([]Middleware(nil), st.middleware...)
+	st.mu.RUnlock()
+
+	for _, f := range cfgs {
+		f(c)
+	}
+	c.queue = make(chan *stripe.Event, c.queueSize)
+	if c.breaker != nil {
+		c.breaker.clock = c.clock
+	}
+	for _, b := range c.rateLimits {
+		b.Now = c.clock.Now
+	}
+	c.typeSems = typeSemaphores(c.maxConcurrencyFor)
+	return c
+}
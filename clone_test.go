@@ -0,0 +1,61 @@
+package stripetotrello
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	stripe "github.com/stripe/stripe-go/v76"
+)
+
+func TestClone(t *testing.T) {
+	noop := func(_ *stripe.Event) (EventResponse, error) {
+		return nil, nil
+	}
+
+	base := NewClient(WithStripeWebhookSecret(testSecret), WithRateLimit("customer.*", 100, 1), WithCircuitBreaker(1, time.Minute))
+	base.AppendHandler("customer.created", noop)
+	base.AppendHandler("customer.updated", noop)
+	base.AddSuccessHandler("customer.created", func(_ *stripe.Event, _ []EventResponse) error {
+		return nil
+	})
+
+	clone := base.Clone(WithStripeWebhookSecrets("whsec_tenant", "whsec_other"))
+	if got := clone.HandlerCount("customer.created"); got != 1 {
+		t.Fatalf("Expected the clone to keep the handlers got %d", got)
+	}
+
+	clone.AppendHandler("customer.created", noop)
+	clone.AppendHandler("invoice.paid", noop)
+	clone.RemoveHandler("customer.updated")
+	clone.AppendSuccessHandler("customer.created", func(_ *stripe.Event, _ []EventResponse) error {
+		return nil
+	})
+	clone.AddFailureHandler("customer.created", func(_ *stripe.Event, err error) error {
+		return err
+	})
+
+	snap := base.Snapshot()
+	if snap.HandlerCounts["customer.created"] != 1 || snap.HandlerCounts["customer.updated"] != 1 || base.HasHandler("invoice.paid") {
+		t.Errorf("Expected the original handlers to be unchanged got %v", snap.HandlerCounts)
+	}
+	if len(base.successHandler["customer.created"]) != 1 || len(snap.FailureHandlers) != 0 {
+		t.Errorf("Expected the original success and failure handlers to be unchanged got %+v", snap)
+	}
+	if snap.WebhookSecrets != 1 || clone.Snapshot().WebhookSecrets != 2 {
+		t.Errorf("Expected each client to keep its own secrets")
+	}
+
+	// The breaker state is not shared, tripping the clone leaves the original closed.
+	failing := base.Clone()
+	failing.AppendHandler("customer.deleted", func(_ *stripe.Event) (EventResponse, error) {
+		return nil, errors.New("test")
+	})
+	failing.Handle(&stripe.Event{ID: "evt_1", Type: "customer.deleted"})
+	if err := failing.Handle(&stripe.Event{ID: "evt_2", Type: "customer.deleted"}); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("Expected the clone breaker to open got %v", err)
+	}
+	if err := base.Handle(&stripe.Event{ID: "evt_3", Type: "customer.created"}); err != nil {
+		t.Errorf("Event should have NOT failed on the original %v", err)
+	}
+}
@@ -0,0 +1,33 @@
+package stripetotrello
+
+import (
+	"context"
+	"net/http"
+
+	stripe "github.com/stripe/stripe-go/v76"
+)
+
+type accountKey struct{}
+
+// WithSecretFunc makes ServeHTTP pick the signing secret per request, e.g.
+// from a header set by a proxy, instead of the configured secrets. An error
+// from f is answered with 500. It is ignored when a Verifier is set.
+func WithSecretFunc(f func(r *http.Request) (string, error)) func(*Client) {
+	return func(c *Client) {
+		c.secretFunc = f
+	}
+}
+
+// AccountFromContext returns the connected account of the event being
+// handled, for Connect events.
+func AccountFromContext(ctx context.Context) (string, bool) {
+	account, ok := ctx.Value(accountKey{}).(string)
+	return account, ok
+}
+
+func withAccount(ctx context.Context, event *stripe.Event) context.Context {
+	if event.Account == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, accountKey{}, event.Account)
+}
@@ -0,0 +1,66 @@
+package stripetotrello
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	stripe "github.com/stripe/stripe-go/v76"
+)
+
+func TestSecretFunc(t *testing.T) {
+	secrets := map[string]string{
+		"acct_1": "whsec_acct_1",
+		"acct_2": "whsec_acct_2",
+	}
+
+	type testCase struct {
+		name    string
+		account string
+		secret  string
+		status  int
+	}
+
+	tcs := []testCase{
+		{"first account", "acct_1", "whsec_acct_1", http.StatusOK},
+		{"second account", "acct_2", "whsec_acct_2", http.StatusOK},
+		{"wrong secret", "acct_2", "whsec_acct_1", http.StatusBadRequest},
+		{"unknown account", "acct_3", "whsec_acct_1", http.StatusInternalServerError},
+	}
+
+	var accounts []string
+	client := NewClient(WithStripeWebhookSecret(testSecret), WithSecretFunc(func(r *http.Request) (string, error) {
+		secret, ok := secrets[r.Header.Get("X-Account")]
+		if !ok {
+			return "", fmt.Errorf("unknown account %q", r.Header.Get("X-Account"))
+		}
+		return secret, nil
+	}))
+	client.AppendHandlerCtx("customer.created", func(ctx context.Context, _ *stripe.Event) (EventResponse, error) {
+		account, _ := AccountFromContext(ctx)
+		accounts = append(accounts, account)
+		return "ok", nil
+	})
+
+	for _, tc := range tcs {
+		accounts = nil
+		payload := []byte(fmt.Sprintf(`{"id": "evt_test", "object": "event", "type": "customer.created", "account": %q, "api_version": %q, "data": {"object": {}}}`, tc.account, stripe.APIVersion))
+		req := httptest.NewRequest(http.MethodPost, "/webhook", bytes.NewReader(payload))
+		req.Header.Set(SIGNATURE_HEADER, signature(tc.secret, payload, time.Now()))
+		req.Header.Set("X-Account", tc.account)
+
+		rec := httptest.NewRecorder()
+		client.ServeHTTP(rec, req)
+
+		if rec.Code != tc.status {
+			t.Errorf("Expected status %d for %s, got %d", tc.status, tc.name, rec.Code)
+		}
+		if tc.status == http.StatusOK && (len(accounts) != 1 || accounts[0] != tc.account) {
+			t.Errorf("Expected %s handlers to see account %s, got %v", tc.name, tc.account, accounts)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package stripetotrello
+
+import (
+	"context"
+
+	stripe "github.com/stripe/stripe-go/v76"
+)
+
+type correlationKey struct{}
+
+// WithCorrelationIDFunc sets how the correlation id of an event is computed,
+// it defaults to the event ID, as does f returning "". The id is in the
+// context handlers get, see CorrelationIDFromContext, and in every log line
+// about the event. f can be called more than once for an event so it should
+// derive the id from it rather than generate a new one.
+func WithCorrelationIDFunc(f func(event *stripe.Event) string) func(*Client) {
+	return func(c *Client) {
+		c.correlationIDFunc = f
+	}
+}
+
+func CorrelationIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(correlationKey{}).(string)
+	return id, ok
+}
+
+func (st *Client) correlationID(event *stripe.Event) string {
+	if st.correlationIDFunc != nil {
+		if id := st.correlationIDFunc(event); id != "" {
+			return id
+		}
+	}
+	return event.ID
+}
+
+func (st *Client) withCorrelationID(ctx context.Context, event *stripe.Event) context.Context {
+	return context.WithValue(ctx, correlationKey{}, st.correlationID(event))
+}
+
+// logFields returns the keys and values identifying the event in a log line
+// followed by keysAndValues, the correlation id comes from ctx when it has
+// one.
+func (st *Client) logFields(ctx context.Context, event *stripe.Event, keysAndValues ...interface{}) []interface{} {
+	id, ok := CorrelationIDFromContext(ctx)
+	if !ok {
+		id = st.correlationID(event)
+	}
+	return append([]interface{}{"event_id", event.ID, "event_type", string(event.Type), "correlation_id", id}, keysAndValues...)
+}
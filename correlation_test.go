@@ -0,0 +1,67 @@
+package stripetotrello
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	stripe "github.com/stripe/stripe-go/v76"
+)
+
+func TestCorrelationID(t *testing.T) {
+	type testCase struct {
+		name     string
+		cfgs     []func(*Client)
+		parallel bool
+		expected string
+	}
+
+	fromMetadata := WithCorrelationIDFunc(func(event *stripe.Event) string {
+		return event.Data.Object["metadata"].(map[string]interface{})["request_id"].(string)
+	})
+
+	tcs := []testCase{
+		{"default", nil, false, "evt_1"},
+		{"default parallel", nil, true, "evt_1"},
+		{"func", []func(*Client){fromMetadata}, false, "req_1"},
+		{"func parallel", []func(*Client){fromMetadata}, true, "req_1"},
+		{"empty func", []func(*Client){WithCorrelationIDFunc(func(*stripe.Event) string { return "" })}, false, "evt_1"},
+	}
+
+	for _, tc := range tcs {
+		logger := &capturingLogger{}
+		client := NewClient(append(tc.cfgs, WithLogger(logger))...)
+
+		var seen []string
+		handler := func(ctx context.Context, _ *stripe.Event) (EventResponse, error) {
+			id, _ := CorrelationIDFromContext(ctx)
+			seen = append(seen, id)
+			return nil, errors.New("downstream failed")
+		}
+		client.AppendHandlerCtx("customer.created", handler)
+		client.AppendHandlerCtx("customer.updated", handler)
+
+		event := &stripe.Event{
+			ID:   "evt_1",
+			Type: "customer.created",
+			Data: &stripe.EventData{Object: map[string]interface{}{"metadata": map[string]interface{}{"request_id": "req_1"}}},
+		}
+		if tc.parallel {
+			client.HandleParallel(event)
+		} else {
+			client.Handle(event)
+		}
+
+		if len(seen) != 1 || seen[0] != tc.expected {
+			t.Errorf("%s: Expected the handler context to carry %s got %v", tc.name, tc.expected, seen)
+		}
+		if len(logger.lines) == 0 {
+			t.Fatalf("%s: Expected the client to log", tc.name)
+		}
+		for _, line := range logger.lines {
+			if id := logger.value(line, "correlation_id"); id != tc.expected {
+				t.Errorf("%s: Expected correlation_id %s in %q got %v", tc.name, tc.expected, line.msg, id)
+			}
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package stripetotrello
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	stripe "github.com/stripe/stripe-go/v76"
+)
+
+type (
+	// DeadLetter receives the events whose dispatch failed for good, after the
+	// retries and the failure handler, so they can be kept somewhere durable.
+	DeadLetter interface {
+		Store(event *stripe.Event, err error) error
+	}
+
+	// RawDeadLetter is implemented by stores that keep the original payload,
+	// StoreRaw is used instead of Store for events dispatched by
+	// HandleRawEvent.
+	RawDeadLetter interface {
+		DeadLetter
+		StoreRaw(event *RawEvent, err error) error
+	}
+
+	// DeadLetterEntry is a stored event, Retryable tells whether its error
+	// was a transient one worth replaying later, see IsRetryable.
+	DeadLetterEntry struct {
+		Event     *stripe.Event
+		Raw       []byte
+		Err       error
+		Retryable bool
+	}
+
+	MemoryDeadLetter struct {
+		mu      sync.Mutex
+		entries []DeadLetterEntry
+	}
+)
+
+func WithDeadLetter(d DeadLetter) func(*Client) {
+	return func(c *Client) {
+		c.deadLetter = d
+	}
+}
+
+func NewMemoryDeadLetter() *MemoryDeadLetter {
+	return &MemoryDeadLetter{}
+}
+
+func (m *MemoryDeadLetter) Store(event *stripe.Event, err error) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.entries = append(m.entries, DeadLetterEntry{Event: event, Err: err, Retryable: IsRetryable(err)})
+	return nil
+}
+
+func (m *MemoryDeadLetter) StoreRaw(event *RawEvent, err error) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.entries = append(m.entries, DeadLetterEntry{Event: event.Event, Raw: event.Raw, Err: err, Retryable: IsRetryable(err)})
+	return nil
+}
+
+// Entries returns a copy of the stored events, oldest first.
+func (m *MemoryDeadLetter) Entries() []DeadLetterEntry {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]DeadLetterEntry(nil), m.entries...)
+}
+
+// storeDeadLetter hands a failed dispatch to the dead letter store, events
+// without a handler are not failures and are left out.
+func (st *Client) storeDeadLetter(ctx context.Context, event *stripe.Event, err error) {
+	if err == nil || st.deadLetter == nil || errors.Is(err, ErrNoHandler) {
+		return
+	}
+	store := func() error { return st.deadLetter.Store(event, err) }
+	if rdl, ok := st.deadLetter.(RawDeadLetter); ok {
+		if raw, ok := st.RawEventFor(event); ok {
+			store = func() error { return rdl.StoreRaw(raw, err) }
+		}
+	}
+	if sErr := store(); sErr != nil {
+		st.logger.Error("dead letter store failed", st.logFields(ctx, event, "error", sErr)...)
+		return
+	}
+	st.logger.Debug("event dead lettered", st.logFields(ctx, event, "retryable", IsRetryable(err))...)
+}
@@ -0,0 +1,63 @@
+package stripetotrello
+
+import (
+	"errors"
+	"testing"
+
+	stripe "github.com/stripe/stripe-go/v76"
+)
+
+func TestDeadLetter(t *testing.T) {
+	cause := errors.New("trello unavailable")
+
+	type testCase struct {
+		name     string
+		event    *stripe.Event
+		failure  StripeFailedEventHandler
+		parallel bool
+		stored   bool
+	}
+
+	tcs := []testCase{
+		{name: "handle", event: &stripe.Event{ID: "evt_1", Type: "customer.created"}, stored: true},
+		{name: "parallel", event: &stripe.Event{ID: "evt_2", Type: "customer.created"}, parallel: true, stored: true},
+		{name: "failure handler error", event: &stripe.Event{ID: "evt_3", Type: "customer.created"}, failure: func(_ *stripe.Event, err error) error { return err }, stored: true},
+		{name: "failure handler resolves", event: &stripe.Event{ID: "evt_4", Type: "customer.created"}, failure: func(_ *stripe.Event, _ error) error { return nil }},
+		{name: "no handler", event: &stripe.Event{ID: "evt_5", Type: "customer.deleted"}},
+	}
+
+	for _, tc := range tcs {
+		dl := NewMemoryDeadLetter()
+		client := NewClient(WithDeadLetter(dl))
+		client.AppendHandler("customer.created", func(_ *stripe.Event) (EventResponse, error) {
+			return nil, cause
+		})
+		if tc.failure != nil {
+			client.AddFailureHandler("customer.created", tc.failure)
+		}
+
+		if tc.parallel {
+			client.HandleParallel(tc.event)
+		} else {
+			client.Handle(tc.event)
+		}
+
+		entries := dl.Entries()
+		if !tc.stored {
+			if len(entries) != 0 {
+				t.Errorf("%s: Expected no dead letter, got %d", tc.name, len(entries))
+			}
+			continue
+		}
+		if len(entries) != 1 {
+			t.Errorf("%s: Expected 1 dead letter, got %d", tc.name, len(entries))
+			continue
+		}
+		if entries[0].Event != tc.event {
+			t.Errorf("%s: Expected event %s in the dead letter store, got %s", tc.name, tc.event.ID, entries[0].Event.ID)
+		}
+		if !errors.Is(entries[0].Err, cause) {
+			t.Errorf("%s: Expected the handler error to be stored, got %v", tc.name, entries[0].Err)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package stripetotrello
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	stripe "github.com/stripe/stripe-go/v76"
+)
+
+type decodedKey struct{}
+
+type decodedObject struct {
+	event  *stripe.Event
+	decode func(raw []byte) (interface{}, error)
+
+	once  sync.Once
+	value interface{}
+	err   error
+}
+
+// WithDecodedType decodes the data object of the events of eventType, or of
+// a pattern, into a T at most once per dispatch, the handlers then get it
+// from DecodedObject instead of each decoding the raw JSON.
+func WithDecodedType[T any](eventType string) func(*Client) {
+	return func(c *Client) {
+		if c.decoders == nil {
+			c.decoders = make(map[string]func(raw []byte) (interface{}, error))
+		}
+		c.decoders[eventType] = func(raw []byte) (interface{}, error) {
+			var obj T
+			if err := json.Unmarshal(raw, &obj); err != nil {
+				return nil, err
+			}
+			return &obj, nil
+		}
+	}
+}
+
+// DecodedObject returns the data object of the event as a T. It is decoded
+// once and shared by the handlers of the event when WithDecodedType
+// registered T for its type, so it must not be modified, and otherwise
+// decoded on every call like UnmarshalEventObject.
+func DecodedObject[T any](ctx context.Context, event *stripe.Event) (*T, error) {
+	if d, ok := ctx.Value(decodedKey{}).(*decodedObject); ok && d.event == event {
+		d.once.Do(func() {
+			d.value, d.err = d.decode(event.Data.Raw)
+		})
+		if d.err != nil {
+			return nil, newError("stripetotrello.DecodedObject", []interface{}{event}, d.err)
+		}
+		if obj, ok := d.value.(*T); ok {
+			return obj, nil
+		}
+	}
+	return UnmarshalEventObject[T](event)
+}
+
+// withDecoded leaves ctx alone when no type is registered for the event, the
+// object is only decoded when a handler asks for it.
+func (st *Client) withDecoded(ctx context.Context, event *stripe.Event) context.Context {
+	if len(st.decoders) == 0 || event.Data == nil {
+		return ctx
+	}
+	decode, ok := match(st.decoders, string(event.Type))
+	if !ok {
+		return ctx
+	}
+	return context.WithValue(ctx, decodedKey{}, &decodedObject{event: event, decode: decode})
+}
@@ -0,0 +1,96 @@
+package stripetotrello
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	stripe "github.com/stripe/stripe-go/v76"
+)
+
+var decodes atomic.Int32
+
+type countedInvoice struct {
+	stripe.Invoice
+}
+
+func (ci *countedInvoice) UnmarshalJSON(data []byte) error {
+	decodes.Add(1)
+	return json.Unmarshal(data, &ci.Invoice)
+}
+
+func TestDecodedObject(t *testing.T) {
+	type testCase struct {
+		name     string
+		cfgs     []func(*Client)
+		parallel bool
+		decodes  int32
+	}
+
+	tcs := []testCase{
+		{"no type registered", nil, false, 3},
+		{"registered", []func(*Client){WithDecodedType[countedInvoice]("invoice.paid")}, false, 1},
+		{"registered pattern", []func(*Client){WithDecodedType[countedInvoice]("invoice.*")}, true, 1},
+		{"other type registered", []func(*Client){WithDecodedType[stripe.Customer]("invoice.paid")}, false, 3},
+	}
+
+	for _, tc := range tcs {
+		decodes.Store(0)
+		client := NewClient(tc.cfgs...)
+		for i := 0; i < 3; i++ {
+			RegisterTyped(client, "invoice.paid", func(_ context.Context, invoice *countedInvoice) (EventResponse, error) {
+				return invoice.ID, nil
+			})
+		}
+
+		event := &stripe.Event{Type: "invoice.paid", Data: &stripe.EventData{Raw: json.RawMessage(`{"id": "in_1", "object": "invoice"}`)}}
+		var err error
+		if tc.parallel {
+			err = client.HandleParallel(event)
+		} else {
+			var res []EventResponse
+			res, err = client.HandleCollect(event)
+			if len(res) != 3 || res[0] != "in_1" {
+				t.Errorf("%s: Expected 3 responses in_1 got %v", tc.name, res)
+			}
+		}
+		if err != nil {
+			t.Fatalf("%s: Event should have NOT failed %v", tc.name, err)
+		}
+		if got := decodes.Load(); got != tc.decodes {
+			t.Errorf("%s: Expected %d decodes got %d", tc.name, tc.decodes, got)
+		}
+	}
+}
+
+func BenchmarkDecodedObject(b *testing.B) {
+	payload := json.RawMessage(`{"id": "in_1", "object": "invoice", "amount_due": 2000, "currency": "usd", "customer": "cus_1",
+		"customer_email": "jenny@example.com", "lines": {"object": "list", "data": [{"id": "il_1", "amount": 2000, "currency": "usd"}]},
+		"status": "paid", "subscription": "sub_1", "total": 2000}`)
+
+	for _, cached := range []bool{false, true} {
+		b.Run(fmt.Sprintf("cached=%t", cached), func(b *testing.B) {
+			var cfgs []func(*Client)
+			if cached {
+				cfgs = append(cfgs, WithDecodedType[stripe.Invoice]("invoice.paid"))
+			}
+			client := NewClient(cfgs...)
+			for i := 0; i < 5; i++ {
+				RegisterTyped(client, "invoice.paid", func(_ context.Context, invoice *stripe.Invoice) (EventResponse, error) {
+					return invoice.ID, nil
+				})
+			}
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				event := &stripe.Event{Type: "invoice.paid", Data: &stripe.EventData{Raw: payload}}
+				if err := client.Handle(event); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
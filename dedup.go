@@ -0,0 +1,120 @@
+package stripetotrello
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	stripe "github.com/stripe/stripe-go/v76"
+)
+
+type (
+	// Deduper reports whether an event id has already been processed. Seen
+	// records the id before the handlers run, so concurrent deliveries of an
+	// event are dispatched once. A Deduper that is not a Forgetter keeps the
+	// id even when the handlers fail, and the Stripe retries are skipped.
+	Deduper interface {
+		Seen(id string) (bool, error)
+	}
+
+	// Forgetter is implemented by the Deduper that can release an id, the
+	// client calls Forget when the dispatch of the event failed so that the
+	// Stripe retry runs the handlers again.
+	Forgetter interface {
+		Forget(id string) error
+	}
+
+	MemoryDeduper struct {
+		size int
+		ttl  time.Duration
+
+		mu      sync.Mutex
+		entries map[string]*list.Element
+		order   *list.List
+	}
+
+	memoryEntry struct {
+		id   string
+		seen time.Time
+	}
+)
+
+// NewMemoryDeduper keeps up to size ids in memory, evicting the least recently
+// seen first. Ids older than ttl are forgotten, a ttl of zero keeps them until
+// evicted.
+func NewMemoryDeduper(size int, ttl time.Duration) *MemoryDeduper {
+	return &MemoryDeduper{
+		size:    size,
+		ttl:     ttl,
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+	}
+}
+
+func WithDeduper(d Deduper) func(*Client) {
+	return func(c *Client) {
+		c.deduper = d
+	}
+}
+
+func (m *MemoryDeduper) Seen(id string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	if el, ok := m.entries[id]; ok {
+		entry := el.Value.(*memoryEntry)
+		if m.ttl <= 0 || now.Sub(entry.seen) < m.ttl {
+			m.order.MoveToFront(el)
+			return true, nil
+		}
+		entry.seen = now
+		m.order.MoveToFront(el)
+		return false, nil
+	}
+
+	m.entries[id] = m.order.PushFront(&memoryEntry{id: id, seen: now})
+	for m.size > 0 && m.order.Len() > m.size {
+		oldest := m.order.Back()
+		m.order.Remove(oldest)
+		delete(m.entries, oldest.Value.(*memoryEntry).id)
+	}
+	return false, nil
+}
+
+func (m *MemoryDeduper) Forget(id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if el, ok := m.entries[id]; ok {
+		m.order.Remove(el)
+		delete(m.entries, id)
+	}
+	return nil
+}
+
+func (st *Client) seen(event *stripe.Event) (bool, error) {
+	if st.deduper == nil || event.ID == "" {
+		return false, nil
+	}
+
+	seen, err := st.deduper.Seen(event.ID)
+	if err != nil {
+		return false, newError("Client.seen", []interface{}{event}, err)
+	}
+	return seen, nil
+}
+
+// forget releases the id of an event whose dispatch failed, events without a
+// handler are not failures and keep it.
+func (st *Client) forget(ctx context.Context, event *stripe.Event, err error) {
+	f, ok := st.deduper.(Forgetter)
+	if !ok || err == nil || event.ID == "" || isReplay(ctx) || errors.Is(err, ErrNoHandler) {
+		return
+	}
+	if fErr := f.Forget(event.ID); fErr != nil {
+		st.logger.Error("deduper forget failed", st.logFields(ctx, event, "error", fErr)...)
+	}
+}
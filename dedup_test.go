@@ -0,0 +1,169 @@
+package stripetotrello
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	stripe "github.com/stripe/stripe-go/v76"
+)
+
+func TestDeduper(t *testing.T) {
+	type testCase struct {
+		event stripe.Event
+		calls int
+	}
+
+	calls := 0
+	client := NewClient(WithDeduper(NewMemoryDeduper(10, time.Hour)))
+	client.AppendHandler("customer.created", func(_ *stripe.Event) (EventResponse, error) {
+		calls++
+		return nil, nil
+	})
+
+	tcs := []testCase{
+		{stripe.Event{ID: "evt_1", Type: "customer.created"}, 1},
+		{stripe.Event{ID: "evt_1", Type: "customer.created"}, 1},
+		{stripe.Event{ID: "evt_2", Type: "customer.created"}, 2},
+		{stripe.Event{ID: "evt_2", Type: "customer.created"}, 2},
+	}
+
+	for _, tc := range tcs {
+		if err := client.Handle(&tc.event); err != nil {
+			t.Errorf("Event should have NOT failed event id = %s, got %s", tc.event.ID, err)
+		}
+		if err := client.HandleParallel(&tc.event); err != nil {
+			t.Errorf("Event should have NOT failed event id = %s, got %s", tc.event.ID, err)
+		}
+		if calls != tc.calls {
+			t.Errorf("Expected handler to run %d times after event id = %s, ran %d", tc.calls, tc.event.ID, calls)
+		}
+	}
+}
+
+func TestDeduperForgetsFailedEvents(t *testing.T) {
+	type testCase struct {
+		fail    bool
+		calls   int
+		succeed bool
+	}
+
+	calls := 0
+	var fail bool
+	client := NewClient(WithDeduper(NewMemoryDeduper(10, time.Hour)))
+	client.AppendHandler("customer.created", func(_ *stripe.Event) (EventResponse, error) {
+		calls++
+		if fail {
+			return nil, errors.New("trello is down")
+		}
+		return nil, nil
+	})
+
+	tcs := []testCase{
+		{true, 1, false},
+		{true, 2, false},
+		{false, 3, true},
+		{false, 3, true},
+	}
+
+	for i, tc := range tcs {
+		fail = tc.fail
+		err := client.Handle(&stripe.Event{ID: "evt_1", Type: "customer.created"})
+		if (err == nil) != tc.succeed {
+			t.Errorf("Expected delivery %d to succeed = %t, got %v", i, tc.succeed, err)
+		}
+		if calls != tc.calls {
+			t.Errorf("Expected handler to run %d times after delivery %d, ran %d", tc.calls, i, calls)
+		}
+	}
+
+	fail = true
+	if err := client.HandleParallel(&stripe.Event{ID: "evt_2", Type: "customer.created"}); err == nil {
+		t.Errorf("Event should have failed event id = evt_2")
+	}
+	fail = false
+	if err := client.HandleParallel(&stripe.Event{ID: "evt_2", Type: "customer.created"}); err != nil || calls != 5 {
+		t.Errorf("Expected HandleParallel to run the retry of a failed event, got %v after %d calls", err, calls)
+	}
+}
+
+func TestDeduperMetrics(t *testing.T) {
+	type testCase struct {
+		id      string
+		deduped bool
+		count   int
+	}
+
+	logger := &capturingLogger{}
+	metrics := newFakeMetrics()
+	client := NewClient(WithDeduper(NewMemoryDeduper(10, time.Hour)), WithLogger(logger), WithMetrics(metrics))
+	client.AppendHandler("customer.created", func(_ *stripe.Event) (EventResponse, error) {
+		return nil, nil
+	})
+
+	tcs := []testCase{
+		{"evt_1", false, 0},
+		{"evt_1", true, 1},
+		{"evt_2", false, 1},
+		{"evt_1", true, 2},
+	}
+
+	for _, tc := range tcs {
+		logger.lines = nil
+		outcome := client.HandleWithOutcome(context.Background(), &stripe.Event{ID: tc.id, Type: "customer.created"})
+		if outcome.Err != nil || outcome.Deduped != tc.deduped || outcome.Handled == tc.deduped {
+			t.Errorf("Expected event id = %s to be deduped = %t, got %+v", tc.id, tc.deduped, outcome)
+		}
+		if got := metrics.counts["customer.created:"+OUTCOME_DEDUPED]; got != tc.count {
+			t.Errorf("Expected %d deduped events after event id = %s, got %d", tc.count, tc.id, got)
+		}
+
+		logged := false
+		for _, line := range logger.lines {
+			if line.msg == "skipping duplicate event" {
+				logged = line.level == "debug" && logger.value(line, "event_id") == tc.id
+			}
+		}
+		if logged != tc.deduped {
+			t.Errorf("Expected event id = %s to log the skip = %t, got %v", tc.id, tc.deduped, logger.lines)
+		}
+	}
+}
+
+func TestMemoryDeduper(t *testing.T) {
+	type testCase struct {
+		id   string
+		seen bool
+	}
+
+	deduper := NewMemoryDeduper(2, 0)
+
+	tcs := []testCase{
+		{"evt_1", false},
+		{"evt_1", true},
+		{"evt_2", false},
+		{"evt_3", false},
+		{"evt_1", false},
+		{"evt_3", true},
+	}
+
+	for _, tc := range tcs {
+		seen, err := deduper.Seen(tc.id)
+		if err != nil {
+			t.Errorf("Expected no error for id = %s, got %s", tc.id, err)
+		}
+		if seen != tc.seen {
+			t.Errorf("Expected seen = %t for id = %s, got %t", tc.seen, tc.id, seen)
+		}
+	}
+
+	expiring := NewMemoryDeduper(10, 10*time.Millisecond)
+	if seen, _ := expiring.Seen("evt_1"); seen {
+		t.Errorf("Expected evt_1 to be new")
+	}
+	time.Sleep(20 * time.Millisecond)
+	if seen, _ := expiring.Seen("evt_1"); seen {
+		t.Errorf("Expected evt_1 to be forgotten after the ttl")
+	}
+}
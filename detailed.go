@@ -0,0 +1,186 @@
+package stripetotrello
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	stripe "github.com/stripe/stripe-go/v76"
+)
+
+type (
+	// Result is the outcome of a single handler, Duration includes the
+	// retries and the backoff between them.
+	Result struct {
+		Response EventResponse
+		Duration time.Duration
+		Err      error
+	}
+
+	// StripeSuccessEventHandlerDetailed is a success handler that receives
+	// the result of every handler, with its duration, instead of the bare
+	// responses.
+	StripeSuccessEventHandlerDetailed func(event *stripe.Event, results []Result) error
+
+	// StripeFailedEventHandlerDetailed is a failure handler that also
+	// receives the results of the handlers that ran, the failed ones
+	// included.
+	StripeFailedEventHandlerDetailed func(event *stripe.Event, results []Result, err error) error
+
+	resultRecorder struct {
+		mu      sync.Mutex
+		results map[int]Result
+	}
+
+	resultRecorderKey struct{}
+)
+
+// HandleDetailed dispatches the event like HandleContext and returns the
+// result of every handler that ran, in handler order, along with the error
+// Handle would return.
+func (st *Client) HandleDetailed(ctx context.Context, event *stripe.Event) ([]Result, error) {
+	rec := &resultRecorder{results: make(map[int]Result)}
+	err := st.HandleContext(context.WithValue(ctx, resultRecorderKey{}, rec), event)
+	return rec.sorted(), err
+}
+
+func (st *Client) HandleParallelDetailed(ctx context.Context, event *stripe.Event) ([]Result, error) {
+	rec := &resultRecorder{results: make(map[int]Result)}
+	err := st.HandleParallelContext(context.WithValue(ctx, resultRecorderKey{}, rec), event)
+	return rec.sorted(), err
+}
+
+// AddSuccessHandlerDetailed sets a success handler getting the []Result of
+// the handlers, it runs after the AddSuccessHandler ones that match the
+// event. It ignores a nil handler, RemoveSuccessHandler drops it.
+func (st *Client) AddSuccessHandlerDetailed(eventType string, handler StripeSuccessEventHandlerDetailed) {
+	if handler == nil {
+		return
+	}
+
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	st.successDetailed[eventType] = handler
+}
+
+// AddFailureHandlerDetailed sets the failure handler of the event type like
+// AddFailureHandler, the handler also gets the []Result of the handlers that
+// ran. It replaces the AddFailureHandler one of the same event type and
+// takes precedence over the ones matching the event through a pattern.
+func (st *Client) AddFailureHandlerDetailed(eventType string, handler StripeFailedEventHandlerDetailed) {
+	if handler == nil {
+		return
+	}
+
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	st.failureDetailed[eventType] = handler
+	delete(st.failureHandler, eventType)
+}
+
+// withResults records the handler results of the event when a detailed
+// success or failure handler can need them, forget must be called once the
+// dispatch is over.
+func (st *Client) withResults(ctx context.Context, event *stripe.Event) (context.Context, func()) {
+	if !st.hasDetailed(string(event.Type)) {
+		return ctx, func() {}
+	}
+
+	rec, ok := ctx.Value(resultRecorderKey{}).(*resultRecorder)
+	if !ok {
+		rec = &resultRecorder{results: make(map[int]Result)}
+		ctx = context.WithValue(ctx, resultRecorderKey{}, rec)
+	}
+	st.results.Store(event, rec)
+	return ctx, func() { st.results.Delete(event) }
+}
+
+func (st *Client) hasDetailed(eventType string) bool {
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+
+	if len(st.successDetailed) == 0 && len(st.failureDetailed) == 0 {
+		return false
+	}
+	_, success := match(st.successDetailed, eventType)
+	_, failure := match(st.failureDetailed, eventType)
+	return success || failure
+}
+
+func (st *Client) resultsFor(event *stripe.Event) []Result {
+	rec, ok := st.results.Load(event)
+	if !ok {
+		return nil
+	}
+	return rec.(*resultRecorder).sorted()
+}
+
+func (st *Client) detailedSuccess(h StripeSuccessEventHandlerDetailed) StripeSuccessEventHandler {
+	return func(event *stripe.Event, _ []EventResponse) error {
+		return h(event, st.resultsFor(event))
+	}
+}
+
+func (st *Client) detailedFailure(h StripeFailedEventHandlerDetailed) StripeFailedEventHandler {
+	return func(event *stripe.Event, err error) error {
+		return h(event, st.resultsFor(event), err)
+	}
+}
+
+func recordResult(ctx context.Context, i int, r Result) {
+	rec, ok := ctx.Value(resultRecorderKey{}).(*resultRecorder)
+	if !ok {
+		return
+	}
+
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	rec.results[i] = r
+}
+
+func (rec *resultRecorder) sorted() []Result {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+
+	indexes := make([]int, 0, len(rec.results))
+	for i := range rec.results {
+		indexes = append(indexes, i)
+	}
+	sort.Ints(indexes)
+
+	results := make([]Result, len(indexes))
+	for j, i := range indexes {
+		results[j] = rec.results[i]
+	}
+	return results
+}
+
+type (
+	finalResponse struct {
+		set bool
+		res EventResponse
+	}
+
+	finalResponseKey struct{}
+)
+
+// HandleResult dispatches the event like HandleCollectContext and returns the
+// response of the success handler registered with AddSuccessHandlerWithErrors,
+// or the handler responses as a []EventResponse when there is none.
+func (st *Client) HandleResult(ctx context.Context, event *stripe.Event) (EventResponse, error) {
+	final := &finalResponse{}
+	results, err := st.HandleCollectContext(context.WithValue(ctx, finalResponseKey{}, final), event)
+	if final.set {
+		return final.res, err
+	}
+	return results, err
+}
+
+func setFinalResponse(ctx context.Context, res EventResponse) {
+	if final, ok := ctx.Value(finalResponseKey{}).(*finalResponse); ok {
+		final.set, final.res = true, res
+	}
+}
@@ -0,0 +1,158 @@
+package stripetotrello
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	stripe "github.com/stripe/stripe-go/v76"
+)
+
+func TestHandleDetailed(t *testing.T) {
+	cause := errors.New("trello unavailable")
+	sleeper := func(d time.Duration, err error) StripeEventHandler {
+		return func(_ *stripe.Event) (EventResponse, error) {
+			time.Sleep(d)
+			if err != nil {
+				return nil, err
+			}
+			return d.String(), nil
+		}
+	}
+
+	client := NewClient(WithContinueOnError())
+	client.AppendHandler("customer.created", sleeper(20*time.Millisecond, nil), sleeper(60*time.Millisecond, nil), sleeper(40*time.Millisecond, cause))
+
+	sleeps := []time.Duration{20 * time.Millisecond, 60 * time.Millisecond, 40 * time.Millisecond}
+	for name, handle := range map[string]func(context.Context, *stripe.Event) ([]Result, error){
+		"HandleDetailed":         client.HandleDetailed,
+		"HandleParallelDetailed": client.HandleParallelDetailed,
+	} {
+		results, err := handle(context.Background(), &stripe.Event{Type: "customer.created"})
+		if !errors.Is(err, cause) {
+			t.Errorf("Expected %s to return the handler error, got %v", name, err)
+		}
+		if len(results) != len(sleeps) {
+			t.Fatalf("Expected %s to return %d results, got %d", name, len(sleeps), len(results))
+		}
+
+		for i, r := range results {
+			if r.Duration < sleeps[i] || r.Duration > sleeps[i]+50*time.Millisecond {
+				t.Errorf("Expected %s result %d to take about %s, got %s", name, i, sleeps[i], r.Duration)
+			}
+		}
+		if results[0].Response != "20ms" || results[0].Err != nil {
+			t.Errorf("Expected %s result 0 to hold the response, got %v", name, results[0])
+		}
+		if !errors.Is(results[2].Err, cause) {
+			t.Errorf("Expected %s result 2 to hold the error, got %v", name, results[2].Err)
+		}
+	}
+}
+
+func TestDetailedSuccessAndFailureHandlers(t *testing.T) {
+	type testCase struct {
+		event   stripe.Event
+		success bool
+		results int
+	}
+
+	cause := errors.New("trello unavailable")
+	sleeper := func(d time.Duration, err error) StripeEventHandler {
+		return func(_ *stripe.Event) (EventResponse, error) {
+			time.Sleep(d)
+			return d.String(), err
+		}
+	}
+
+	client := NewClient()
+	client.AppendHandler("customer.created", sleeper(10*time.Millisecond, nil), sleeper(20*time.Millisecond, nil))
+	client.AppendHandler("customer.deleted", sleeper(10*time.Millisecond, nil), sleeper(20*time.Millisecond, cause))
+
+	var got []Result
+	var plain bool
+	client.AddSuccessHandler("customer.*", func(_ *stripe.Event, _ []EventResponse) error {
+		plain = true
+		return nil
+	})
+	client.AddSuccessHandlerDetailed("customer.*", func(_ *stripe.Event, results []Result) error {
+		got = results
+		return nil
+	})
+	client.AddFailureHandlerDetailed("customer.*", func(_ *stripe.Event, results []Result, err error) error {
+		got = results
+		return err
+	})
+
+	tcs := []testCase{
+		{stripe.Event{Type: "customer.created"}, true, 2},
+		{stripe.Event{Type: "customer.deleted"}, false, 2},
+	}
+
+	for _, tc := range tcs {
+		for name, handle := range map[string]func(*stripe.Event) error{
+			"Handle":         client.Handle,
+			"HandleParallel": client.HandleParallel,
+		} {
+			got, plain = nil, false
+			err := handle(&tc.event)
+			if tc.success != (err == nil) || (!tc.success && !errors.Is(err, cause)) {
+				t.Errorf("Expected %s of %s to succeed = %t, got %v", name, tc.event.Type, tc.success, err)
+			}
+			if plain != tc.success {
+				t.Errorf("Expected %s of %s to run the plain success handler = %t", name, tc.event.Type, tc.success)
+			}
+			if len(got) != tc.results {
+				t.Fatalf("Expected %s of %s to pass %d results, got %v", name, tc.event.Type, tc.results, got)
+			}
+			for i, d := range []time.Duration{10 * time.Millisecond, 20 * time.Millisecond} {
+				if got[i].Duration < d {
+					t.Errorf("Expected %s of %s result %d to take at least %s, got %s", name, tc.event.Type, i, d, got[i].Duration)
+				}
+			}
+			if !tc.success && !errors.Is(got[1].Err, cause) {
+				t.Errorf("Expected %s of %s to pass the failed result, got %v", name, tc.event.Type, got[1])
+			}
+		}
+	}
+
+	if !client.RemoveFailureHandler("customer.*") {
+		t.Errorf("Expected RemoveFailureHandler to drop the detailed failure handler")
+	}
+	if err := client.Handle(&stripe.Event{Type: "customer.deleted"}); !errors.Is(err, cause) {
+		t.Errorf("Expected the handler error without a failure handler, got %v", err)
+	}
+}
+
+func TestHandleResult(t *testing.T) {
+	client := NewClient()
+	client.AppendHandler("customer.created", func(_ *stripe.Event) (EventResponse, error) {
+		return "card", nil
+	}, func(_ *stripe.Event) (EventResponse, error) {
+		return "label", nil
+	})
+	client.AddSuccessHandlerWithErrors("customer.created", func(_ *stripe.Event, res []EventResponse, _ StripeEventErrors) (EventResponse, error) {
+		return fmt.Sprintf("%d updates", len(res)), nil
+	})
+	client.AppendHandler("customer.updated", func(_ *stripe.Event) (EventResponse, error) {
+		return "card", nil
+	})
+
+	res, err := client.HandleResult(context.Background(), &stripe.Event{Type: "customer.created"})
+	if err != nil {
+		t.Fatalf("Event should have NOT failed, got %s", err)
+	}
+	if res != "2 updates" {
+		t.Errorf("Expected the success handler response, got %v", res)
+	}
+
+	res, err = client.HandleResult(context.Background(), &stripe.Event{Type: "customer.updated"})
+	if err != nil {
+		t.Fatalf("Event should have NOT failed, got %s", err)
+	}
+	if results, ok := res.([]EventResponse); !ok || len(results) != 1 || results[0] != "card" {
+		t.Errorf("Expected the handler responses without a success handler, got %v", res)
+	}
+}
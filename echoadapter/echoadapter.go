@@ -0,0 +1,21 @@
+package echoadapter
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/skipper-digital-studio/stripetotrello"
+)
+
+// Handler verifies and dispatches the webhook with Client.HandleRequest, the
+// handlers get the request context. An error the client maps to a 4xx or 5xx
+// status is reported as an echo.HTTPError with that status.
+func Handler(c *stripetotrello.Client) echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		status, err := c.HandleRequest(ctx.Request())
+		if status >= http.StatusBadRequest {
+			return echo.NewHTTPError(status).SetInternal(err)
+		}
+		return ctx.NoContent(status)
+	}
+}
@@ -0,0 +1,90 @@
+package echoadapter
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/skipper-digital-studio/stripetotrello"
+	"github.com/skipper-digital-studio/stripetotrello/testutil"
+	stripe "github.com/stripe/stripe-go/v76"
+)
+
+const testSecret = "whsec_test"
+
+func TestHandler(t *testing.T) {
+	type testCase struct {
+		name   string
+		secret string
+		event  string
+		status int
+	}
+
+	client := stripetotrello.NewClient(stripetotrello.WithStripeWebhookSecret(testSecret), stripetotrello.WithMaxBodyBytes(256))
+	client.AppendHandler("customer.created", func(_ *stripe.Event) (stripetotrello.EventResponse, error) {
+		return "ok", nil
+	})
+	client.AppendHandler("customer.deleted", func(_ *stripe.Event) (stripetotrello.EventResponse, error) {
+		return nil, fmt.Errorf("test")
+	})
+
+	tcs := []testCase{
+		{"success", testSecret, "customer.created", http.StatusOK},
+		{"invalid signature", "whsec_other", "customer.created", http.StatusBadRequest},
+		{"handler failure", testSecret, "customer.deleted", http.StatusInternalServerError},
+		{"unhandled event type", testSecret, "invoice.paid", http.StatusOK},
+		{"too large", testSecret, "customer.created" + strings.Repeat(" ", 256), http.StatusRequestEntityTooLarge},
+	}
+
+	e := echo.New()
+	handler := Handler(client)
+	for _, tc := range tcs {
+		payload := testutil.Payload(tc.event)
+		req := httptest.NewRequest(http.MethodPost, "/webhook", bytes.NewReader(payload))
+		req.Header.Set(stripetotrello.SIGNATURE_HEADER, testutil.SignPayload(tc.secret, payload, time.Now()))
+		rec := httptest.NewRecorder()
+
+		err := handler(e.NewContext(req, rec))
+		if tc.status == http.StatusOK {
+			if err != nil || rec.Code != http.StatusOK {
+				t.Errorf("Expected %s to succeed, got %d and %v", tc.name, rec.Code, err)
+			}
+			continue
+		}
+
+		var httpErr *echo.HTTPError
+		if !errors.As(err, &httpErr) || httpErr.Code != tc.status {
+			t.Errorf("Expected an echo.HTTPError with status %d for %s, got %v", tc.status, tc.name, err)
+		}
+	}
+}
+
+type requestKey struct{}
+
+func TestHandlerContext(t *testing.T) {
+	client := stripetotrello.NewClient(stripetotrello.WithStripeWebhookSecret(testSecret))
+	var value interface{}
+	client.AppendHandlerCtx("customer.created", func(ctx context.Context, _ *stripe.Event) (stripetotrello.EventResponse, error) {
+		value = ctx.Value(requestKey{})
+		return nil, nil
+	})
+
+	payload := testutil.Payload("customer.created")
+	req := httptest.NewRequest(http.MethodPost, "/webhook", bytes.NewReader(payload))
+	req.Header.Set(stripetotrello.SIGNATURE_HEADER, testutil.SignPayload(testSecret, payload, time.Now()))
+	req = req.WithContext(context.WithValue(req.Context(), requestKey{}, "request"))
+
+	if err := Handler(client)(echo.New().NewContext(req, httptest.NewRecorder())); err != nil {
+		t.Fatalf("Expected the event to NOT fail, got %s", err)
+	}
+	if value != "request" {
+		t.Errorf("Expected the handlers to get the request context, got %v", value)
+	}
+}
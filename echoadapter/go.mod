@@ -0,0 +1,23 @@
+module github.com/skipper-digital-studio/stripetotrello/echoadapter
+
+go 1.22.3
+
+require (
+	github.com/labstack/echo/v4 v4.12.0
+	github.com/skipper-digital-studio/stripetotrello v0.0.0-00010101000000-000000000000
+	github.com/stripe/stripe-go/v76 v76.25.0
+)
+
+require (
+	github.com/labstack/gommon v0.4.2 // indirect
+	github.com/mattn/go-colorable v0.1.13 // indirect
+	github.com/mattn/go-isatty v0.0.20 // indirect
+	github.com/valyala/bytebufferpool v1.0.0 // indirect
+	github.com/valyala/fasttemplate v1.2.2 // indirect
+	golang.org/x/crypto v0.23.0 // indirect
+	golang.org/x/net v0.25.0 // indirect
+	golang.org/x/sys v0.21.0 // indirect
+	golang.org/x/text v0.15.0 // indirect
+)
+
+replace github.com/skipper-digital-studio/stripetotrello => ../
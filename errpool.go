@@ -0,0 +1,37 @@
+package stripetotrello
+
+import "sync"
+
+// MAX_POOLED_ERRORS caps the capacity of the buffers kept in errsPool so an
+// event with many handlers does not pin a large one.
+const MAX_POOLED_ERRORS = 64
+
+var errsPool = sync.Pool{
+	New: func() interface{} {
+		buf := make([]StripeEventError, 0, 8)
+		return &buf
+	},
+}
+
+func getErrs() *[]StripeEventError {
+	return errsPool.Get().(*[]StripeEventError)
+}
+
+// putErrs clears buf so the pool does not keep the errors alive, it must not
+// be used afterwards.
+func putErrs(buf *[]StripeEventError) {
+	if cap(*buf) > MAX_POOLED_ERRORS {
+		return
+	}
+	clear(*buf)
+	*buf = (*buf)[:0]
+	errsPool.Put(buf)
+}
+
+// detach copies the collected errors out of a pooled buffer, the copy is what
+// failure handlers and callers get so they can keep it.
+func detach(buf []StripeEventError) StripeEventErrors {
+	errs := make(StripeEventErrors, len(buf))
+	copy(errs, buf)
+	return errs
+}
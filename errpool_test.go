@@ -0,0 +1,26 @@
+package stripetotrello
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDetachedErrorsOutlivePool(t *testing.T) {
+	buf := getErrs()
+	*buf = append(*buf, newError("first", nil, errors.New("first")))
+	errs := detach(*buf)
+	putErrs(buf)
+
+	for i := 0; i < 10; i++ {
+		buf := getErrs()
+		if len(*buf) != 0 {
+			t.Fatalf("Expected an empty buffer from the pool got %v", *buf)
+		}
+		*buf = append(*buf, newError("second", nil, errors.New("second")))
+		putErrs(buf)
+	}
+
+	if len(errs) != 1 || errs[0].fn != "first" {
+		t.Errorf("Expected the detached errors to be unchanged got %v", errs)
+	}
+}
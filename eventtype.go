@@ -0,0 +1,69 @@
+package stripetotrello
+
+import (
+	"context"
+	"strings"
+
+	stripe "github.com/stripe/stripe-go/v76"
+)
+
+//go:generate go run ./internal/geneventtypes
+
+// EventType is the type of an event or a handler pattern, see AppendHandler.
+// The EventType constants are generated from the stripe-go ones as untyped
+// strings, so they can be passed to the registration methods as they are,
+// e.g. AppendHandler(EventTypeInvoicePaid, h). Use WithStrictEventTypes to
+// catch the typos of the literal ones.
+type EventType string
+
+// WithStrictEventTypes rejects the registration of handlers for event types
+// that are not an EventType constant, or patterns matching none of them. The
+// AppendHandler methods log the error, AppendHandlerChecked returns it.
+func WithStrictEventTypes() func(*Client) {
+	return func(c *Client) {
+		c.strictEventTypes = true
+	}
+}
+
+// Valid reports whether t is one of the event types the stripe-go version
+// the constants are generated from knows about. Patterns are not valid.
+func (t EventType) Valid() bool {
+	_, ok := knownEventTypes[t]
+	return ok
+}
+
+func knownEventType(eventType string) bool {
+	if EventType(eventType).Valid() {
+		return true
+	}
+	if eventType == WILDCARD {
+		return true
+	}
+	if !strings.HasSuffix(eventType, WILDCARD) {
+		return false
+	}
+	prefix := strings.TrimSuffix(eventType, WILDCARD)
+	for known := range knownEventTypes {
+		if strings.HasPrefix(string(known), prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkEventType records an event whose type this stripe-go version predates
+// as OUTCOME_UNKNOWN_TYPE and one of a known type without handlers as
+// OUTCOME_UNHANDLED. Bridged thin events are not checked, their types are
+// not in the v1 set.
+func (st *Client) checkEventType(ctx context.Context, event *stripe.Event, err error) {
+	eventType := EventType(event.Type)
+	if event.Object != THIN_EVENT_OBJECT && !eventType.Valid() {
+		st.metrics.IncEvent(string(eventType), OUTCOME_UNKNOWN_TYPE)
+		st.logger.Info("unknown event type", st.logFields(ctx, event)...)
+		return
+	}
+	if _, ok := err.(StripeUnsupportedEventError); ok {
+		st.metrics.IncEvent(string(eventType), OUTCOME_UNHANDLED)
+		st.logger.Debug("no handler for event", st.logFields(ctx, event)...)
+	}
+}
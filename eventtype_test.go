@@ -0,0 +1,119 @@
+package stripetotrello
+
+import (
+	"errors"
+	"testing"
+
+	stripe "github.com/stripe/stripe-go/v76"
+)
+
+func TestStrictEventTypes(t *testing.T) {
+	type testCase struct {
+		name      string
+		eventType EventType
+		known     bool
+	}
+
+	tcs := []testCase{
+		{"constant", EventTypeInvoicePaymentFailed, true},
+		{"string literal", "customer.created", true},
+		{"pattern", "invoice.*", true},
+		{"wildcard", WILDCARD, true},
+		{"typo", "invoice.payment_faild", false},
+		{"unknown pattern", "invoices.*", false},
+	}
+
+	noop := func(_ *stripe.Event) (EventResponse, error) {
+		return nil, nil
+	}
+
+	for _, tc := range tcs {
+		logger := &capturingLogger{}
+		client := NewClient(WithStrictEventTypes(), WithLogger(logger))
+
+		err := client.AppendHandlerChecked(string(tc.eventType), noop)
+		if tc.known && err != nil {
+			t.Errorf("Expected %s to be accepted, got %s", tc.name, err)
+		}
+		if !tc.known && !errors.Is(err, ErrUnknownEventType) {
+			t.Errorf("Expected %s to fail with ErrUnknownEventType, got %v", tc.name, err)
+		}
+
+		client.AppendHandler(string(tc.eventType), noop)
+		if count := client.HandlerCount(string(tc.eventType)); (count == 2) != tc.known {
+			t.Errorf("Expected %s to be registered = %t, got %d handlers", tc.name, tc.known, count)
+		}
+		if logged := len(logger.lines) > 0; logged == tc.known {
+			t.Errorf("Expected %s to log the rejection = %t", tc.name, !tc.known)
+		}
+	}
+
+	client := NewClient(WithStrictEventTypes())
+	client.AppendHandler(EventTypeInvoicePaid, noop)
+	if err := client.AppendHandlerChecked(EventTypeInvoicePaymentFailed, noop); err != nil {
+		t.Errorf("Expected a bare constant to be accepted, got %s", err)
+	}
+	if !client.HasHandler(EventTypeInvoicePaid) || client.HandlerCount(EventTypeInvoicePaymentFailed) != 1 {
+		t.Errorf("Expected the constants to register handlers for their event type")
+	}
+	if err := client.Handle(&stripe.Event{Type: EventTypeInvoicePaid}); err != nil {
+		t.Errorf("Event should have NOT failed event type = %s, got %s", EventTypeInvoicePaid, err)
+	}
+
+	client = NewClient()
+	client.AppendHandler("invoice.payment_faild", noop)
+	if !client.HasHandler("invoice.payment_faild") {
+		t.Errorf("Expected unknown event types to be accepted outside strict mode")
+	}
+}
+
+func TestEventTypeValid(t *testing.T) {
+	type testCase struct {
+		name      string
+		eventType stripe.EventType
+		handled   bool
+		outcome   string
+		msg       string
+	}
+
+	tcs := []testCase{
+		{"recognized", "invoice.paid", true, OUTCOME_RECEIVED, ""},
+		{"recognized without handler", "customer.created", false, OUTCOME_UNHANDLED, "no handler for event"},
+		{"unrecognized", "invoice.overpaid", true, OUTCOME_UNKNOWN_TYPE, "unknown event type"},
+		{"unrecognized without handler", "customer.teleported", false, OUTCOME_UNKNOWN_TYPE, "unknown event type"},
+	}
+
+	noop := func(_ *stripe.Event) (EventResponse, error) {
+		return nil, nil
+	}
+
+	for _, tc := range tcs {
+		if valid := EventType(tc.eventType).Valid(); valid != (tc.outcome != OUTCOME_UNKNOWN_TYPE) {
+			t.Errorf("Expected %s to be valid = %t", tc.name, !valid)
+		}
+
+		logger := &capturingLogger{}
+		metrics := newFakeMetrics()
+		client := NewClient(WithLogger(logger), WithMetrics(metrics))
+		client.AppendHandler("invoice.*", noop)
+
+		err := client.Handle(&stripe.Event{ID: "evt_test", Type: tc.eventType, Data: &stripe.EventData{}})
+		if tc.handled == errors.Is(err, ErrNoHandler) {
+			t.Errorf("Expected %s to be handled = %t, got %v", tc.name, tc.handled, err)
+		}
+		if metrics.counts[string(tc.eventType)+":"+tc.outcome] != 1 {
+			t.Errorf("Expected %s to count %s once, got %v", tc.name, tc.outcome, metrics.counts)
+		}
+		if tc.outcome == OUTCOME_UNKNOWN_TYPE && metrics.counts[string(tc.eventType)+":"+OUTCOME_UNHANDLED] != 0 {
+			t.Errorf("Expected %s to NOT be counted as unhandled, got %v", tc.name, metrics.counts)
+		}
+
+		logged := false
+		for _, line := range logger.lines {
+			logged = logged || line.msg == tc.msg
+		}
+		if logged != (tc.msg != "") {
+			t.Errorf("Expected %s to log %q, got %v", tc.name, tc.msg, logger.lines)
+		}
+	}
+}
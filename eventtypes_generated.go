@@ -0,0 +1,477 @@
+// Code generated by internal/geneventtypes from github.com/stripe/stripe-go/v76; DO NOT EDIT.
+
+package stripetotrello
+
+const (
+	EventTypeAccountApplicationAuthorized                       = "account.application.authorized"
+	EventTypeAccountApplicationDeauthorized                     = "account.application.deauthorized"
+	EventTypeAccountExternalAccountCreated                      = "account.external_account.created"
+	EventTypeAccountExternalAccountDeleted                      = "account.external_account.deleted"
+	EventTypeAccountExternalAccountUpdated                      = "account.external_account.updated"
+	EventTypeAccountUpdated                                     = "account.updated"
+	EventTypeApplicationFeeCreated                              = "application_fee.created"
+	EventTypeApplicationFeeRefundUpdated                        = "application_fee.refund.updated"
+	EventTypeApplicationFeeRefunded                             = "application_fee.refunded"
+	EventTypeBalanceAvailable                                   = "balance.available"
+	EventTypeBillingPortalConfigurationCreated                  = "billing_portal.configuration.created"
+	EventTypeBillingPortalConfigurationUpdated                  = "billing_portal.configuration.updated"
+	EventTypeBillingPortalSessionCreated                        = "billing_portal.session.created"
+	EventTypeCapabilityUpdated                                  = "capability.updated"
+	EventTypeCashBalanceFundsAvailable                          = "cash_balance.funds_available"
+	EventTypeChargeCaptured                                     = "charge.captured"
+	EventTypeChargeDisputeClosed                                = "charge.dispute.closed"
+	EventTypeChargeDisputeCreated                               = "charge.dispute.created"
+	EventTypeChargeDisputeFundsReinstated                       = "charge.dispute.funds_reinstated"
+	EventTypeChargeDisputeFundsWithdrawn                        = "charge.dispute.funds_withdrawn"
+	EventTypeChargeDisputeUpdated                               = "charge.dispute.updated"
+	EventTypeChargeExpired                                      = "charge.expired"
+	EventTypeChargeFailed                                       = "charge.failed"
+	EventTypeChargePending                                      = "charge.pending"
+	EventTypeChargeRefundUpdated                                = "charge.refund.updated"
+	EventTypeChargeRefunded                                     = "charge.refunded"
+	EventTypeChargeSucceeded                                    = "charge.succeeded"
+	EventTypeChargeUpdated                                      = "charge.updated"
+	EventTypeCheckoutSessionAsyncPaymentFailed                  = "checkout.session.async_payment_failed"
+	EventTypeCheckoutSessionAsyncPaymentSucceeded               = "checkout.session.async_payment_succeeded"
+	EventTypeCheckoutSessionCompleted                           = "checkout.session.completed"
+	EventTypeCheckoutSessionExpired                             = "checkout.session.expired"
+	EventTypeClimateOrderCanceled                               = "climate.order.canceled"
+	EventTypeClimateOrderCreated                                = "climate.order.created"
+	EventTypeClimateOrderDelayed                                = "climate.order.delayed"
+	EventTypeClimateOrderDelivered                              = "climate.order.delivered"
+	EventTypeClimateOrderProductSubstituted                     = "climate.order.product_substituted"
+	EventTypeClimateProductCreated                              = "climate.product.created"
+	EventTypeClimateProductPricingUpdated                       = "climate.product.pricing_updated"
+	EventTypeCouponCreated                                      = "coupon.created"
+	EventTypeCouponDeleted                                      = "coupon.deleted"
+	EventTypeCouponUpdated                                      = "coupon.updated"
+	EventTypeCreditNoteCreated                                  = "credit_note.created"
+	EventTypeCreditNoteUpdated                                  = "credit_note.updated"
+	EventTypeCreditNoteVoided                                   = "credit_note.voided"
+	EventTypeCustomerCreated                                    = "customer.created"
+	EventTypeCustomerDeleted                                    = "customer.deleted"
+	EventTypeCustomerDiscountCreated                            = "customer.discount.created"
+	EventTypeCustomerDiscountDeleted                            = "customer.discount.deleted"
+	EventTypeCustomerDiscountUpdated                            = "customer.discount.updated"
+	EventTypeCustomerSourceCreated                              = "customer.source.created"
+	EventTypeCustomerSourceDeleted                              = "customer.source.deleted"
+	EventTypeCustomerSourceExpiring                             = "customer.source.expiring"
+	EventTypeCustomerSourceUpdated                              = "customer.source.updated"
+	EventTypeCustomerSubscriptionCreated                        = "customer.subscription.created"
+	EventTypeCustomerSubscriptionDeleted                        = "customer.subscription.deleted"
+	EventTypeCustomerSubscriptionPaused                         = "customer.subscription.paused"
+	EventTypeCustomerSubscriptionPendingUpdateApplied           = "customer.subscription.pending_update_applied"
+	EventTypeCustomerSubscriptionPendingUpdateExpired           = "customer.subscription.pending_update_expired"
+	EventTypeCustomerSubscriptionResumed                        = "customer.subscription.resumed"
+	EventTypeCustomerSubscriptionTrialWillEnd                   = "customer.subscription.trial_will_end"
+	EventTypeCustomerSubscriptionUpdated                        = "customer.subscription.updated"
+	EventTypeCustomerTaxIDCreated                               = "customer.tax_id.created"
+	EventTypeCustomerTaxIDDeleted                               = "customer.tax_id.deleted"
+	EventTypeCustomerTaxIDUpdated                               = "customer.tax_id.updated"
+	EventTypeCustomerUpdated                                    = "customer.updated"
+	EventTypeCustomerCashBalanceTransactionCreated              = "customer_cash_balance_transaction.created"
+	EventTypeFileCreated                                        = "file.created"
+	EventTypeFinancialConnectionsAccountCreated                 = "financial_connections.account.created"
+	EventTypeFinancialConnectionsAccountDeactivated             = "financial_connections.account.deactivated"
+	EventTypeFinancialConnectionsAccountDisconnected            = "financial_connections.account.disconnected"
+	EventTypeFinancialConnectionsAccountReactivated             = "financial_connections.account.reactivated"
+	EventTypeFinancialConnectionsAccountRefreshedBalance        = "financial_connections.account.refreshed_balance"
+	EventTypeFinancialConnectionsAccountRefreshedOwnership      = "financial_connections.account.refreshed_ownership"
+	EventTypeFinancialConnectionsAccountRefreshedTransactions   = "financial_connections.account.refreshed_transactions"
+	EventTypeIdentityVerificationSessionCanceled                = "identity.verification_session.canceled"
+	EventTypeIdentityVerificationSessionCreated                 = "identity.verification_session.created"
+	EventTypeIdentityVerificationSessionProcessing              = "identity.verification_session.processing"
+	EventTypeIdentityVerificationSessionRedacted                = "identity.verification_session.redacted"
+	EventTypeIdentityVerificationSessionRequiresInput           = "identity.verification_session.requires_input"
+	EventTypeIdentityVerificationSessionVerified                = "identity.verification_session.verified"
+	EventTypeInvoiceCreated                                     = "invoice.created"
+	EventTypeInvoiceDeleted                                     = "invoice.deleted"
+	EventTypeInvoiceFinalizationFailed                          = "invoice.finalization_failed"
+	EventTypeInvoiceFinalized                                   = "invoice.finalized"
+	EventTypeInvoiceMarkedUncollectible                         = "invoice.marked_uncollectible"
+	EventTypeInvoicePaid                                        = "invoice.paid"
+	EventTypeInvoicePaymentActionRequired                       = "invoice.payment_action_required"
+	EventTypeInvoicePaymentFailed                               = "invoice.payment_failed"
+	EventTypeInvoicePaymentSucceeded                            = "invoice.payment_succeeded"
+	EventTypeInvoiceSent                                        = "invoice.sent"
+	EventTypeInvoiceUpcoming                                    = "invoice.upcoming"
+	EventTypeInvoiceUpdated                                     = "invoice.updated"
+	EventTypeInvoiceVoided                                      = "invoice.voided"
+	EventTypeInvoiceItemCreated                                 = "invoiceitem.created"
+	EventTypeInvoiceItemDeleted                                 = "invoiceitem.deleted"
+	EventTypeIssuingAuthorizationCreated                        = "issuing_authorization.created"
+	EventTypeIssuingAuthorizationRequest                        = "issuing_authorization.request"
+	EventTypeIssuingAuthorizationUpdated                        = "issuing_authorization.updated"
+	EventTypeIssuingCardCreated                                 = "issuing_card.created"
+	EventTypeIssuingCardUpdated                                 = "issuing_card.updated"
+	EventTypeIssuingCardholderCreated                           = "issuing_cardholder.created"
+	EventTypeIssuingCardholderUpdated                           = "issuing_cardholder.updated"
+	EventTypeIssuingDisputeClosed                               = "issuing_dispute.closed"
+	EventTypeIssuingDisputeCreated                              = "issuing_dispute.created"
+	EventTypeIssuingDisputeFundsReinstated                      = "issuing_dispute.funds_reinstated"
+	EventTypeIssuingDisputeSubmitted                            = "issuing_dispute.submitted"
+	EventTypeIssuingDisputeUpdated                              = "issuing_dispute.updated"
+	EventTypeIssuingTokenCreated                                = "issuing_token.created"
+	EventTypeIssuingTokenUpdated                                = "issuing_token.updated"
+	EventTypeIssuingTransactionCreated                          = "issuing_transaction.created"
+	EventTypeIssuingTransactionUpdated                          = "issuing_transaction.updated"
+	EventTypeMandateUpdated                                     = "mandate.updated"
+	EventTypePaymentIntentAmountCapturableUpdated               = "payment_intent.amount_capturable_updated"
+	EventTypePaymentIntentCanceled                              = "payment_intent.canceled"
+	EventTypePaymentIntentCreated                               = "payment_intent.created"
+	EventTypePaymentIntentPartiallyFunded                       = "payment_intent.partially_funded"
+	EventTypePaymentIntentPaymentFailed                         = "payment_intent.payment_failed"
+	EventTypePaymentIntentProcessing                            = "payment_intent.processing"
+	EventTypePaymentIntentRequiresAction                        = "payment_intent.requires_action"
+	EventTypePaymentIntentSucceeded                             = "payment_intent.succeeded"
+	EventTypePaymentLinkCreated                                 = "payment_link.created"
+	EventTypePaymentLinkUpdated                                 = "payment_link.updated"
+	EventTypePaymentMethodAttached                              = "payment_method.attached"
+	EventTypePaymentMethodAutomaticallyUpdated                  = "payment_method.automatically_updated"
+	EventTypePaymentMethodDetached                              = "payment_method.detached"
+	EventTypePaymentMethodUpdated                               = "payment_method.updated"
+	EventTypePayoutCanceled                                     = "payout.canceled"
+	EventTypePayoutCreated                                      = "payout.created"
+	EventTypePayoutFailed                                       = "payout.failed"
+	EventTypePayoutPaid                                         = "payout.paid"
+	EventTypePayoutReconciliationCompleted                      = "payout.reconciliation_completed"
+	EventTypePayoutUpdated                                      = "payout.updated"
+	EventTypePersonCreated                                      = "person.created"
+	EventTypePersonDeleted                                      = "person.deleted"
+	EventTypePersonUpdated                                      = "person.updated"
+	EventTypePlanCreated                                        = "plan.created"
+	EventTypePlanDeleted                                        = "plan.deleted"
+	EventTypePlanUpdated                                        = "plan.updated"
+	EventTypePriceCreated                                       = "price.created"
+	EventTypePriceDeleted                                       = "price.deleted"
+	EventTypePriceUpdated                                       = "price.updated"
+	EventTypeProductCreated                                     = "product.created"
+	EventTypeProductDeleted                                     = "product.deleted"
+	EventTypeProductUpdated                                     = "product.updated"
+	EventTypePromotionCodeCreated                               = "promotion_code.created"
+	EventTypePromotionCodeUpdated                               = "promotion_code.updated"
+	EventTypeQuoteAccepted                                      = "quote.accepted"
+	EventTypeQuoteCanceled                                      = "quote.canceled"
+	EventTypeQuoteCreated                                       = "quote.created"
+	EventTypeQuoteFinalized                                     = "quote.finalized"
+	EventTypeRadarEarlyFraudWarningCreated                      = "radar.early_fraud_warning.created"
+	EventTypeRadarEarlyFraudWarningUpdated                      = "radar.early_fraud_warning.updated"
+	EventTypeRefundCreated                                      = "refund.created"
+	EventTypeRefundUpdated                                      = "refund.updated"
+	EventTypeReportingReportRunFailed                           = "reporting.report_run.failed"
+	EventTypeReportingReportRunSucceeded                        = "reporting.report_run.succeeded"
+	EventTypeReportingReportTypeUpdated                         = "reporting.report_type.updated"
+	EventTypeReviewClosed                                       = "review.closed"
+	EventTypeReviewOpened                                       = "review.opened"
+	EventTypeSetupIntentCanceled                                = "setup_intent.canceled"
+	EventTypeSetupIntentCreated                                 = "setup_intent.created"
+	EventTypeSetupIntentRequiresAction                          = "setup_intent.requires_action"
+	EventTypeSetupIntentSetupFailed                             = "setup_intent.setup_failed"
+	EventTypeSetupIntentSucceeded                               = "setup_intent.succeeded"
+	EventTypeSigmaScheduledQueryRunCreated                      = "sigma.scheduled_query_run.created"
+	EventTypeSourceCanceled                                     = "source.canceled"
+	EventTypeSourceChargeable                                   = "source.chargeable"
+	EventTypeSourceFailed                                       = "source.failed"
+	EventTypeSourceMandateNotification                          = "source.mandate_notification"
+	EventTypeSourceRefundAttributesRequired                     = "source.refund_attributes_required"
+	EventTypeSourceTransactionCreated                           = "source.transaction.created"
+	EventTypeSourceTransactionUpdated                           = "source.transaction.updated"
+	EventTypeSubscriptionScheduleAborted                        = "subscription_schedule.aborted"
+	EventTypeSubscriptionScheduleCanceled                       = "subscription_schedule.canceled"
+	EventTypeSubscriptionScheduleCompleted                      = "subscription_schedule.completed"
+	EventTypeSubscriptionScheduleCreated                        = "subscription_schedule.created"
+	EventTypeSubscriptionScheduleExpiring                       = "subscription_schedule.expiring"
+	EventTypeSubscriptionScheduleReleased                       = "subscription_schedule.released"
+	EventTypeSubscriptionScheduleUpdated                        = "subscription_schedule.updated"
+	EventTypeTaxSettingsUpdated                                 = "tax.settings.updated"
+	EventTypeTaxRateCreated                                     = "tax_rate.created"
+	EventTypeTaxRateUpdated                                     = "tax_rate.updated"
+	EventTypeTerminalReaderActionFailed                         = "terminal.reader.action_failed"
+	EventTypeTerminalReaderActionSucceeded                      = "terminal.reader.action_succeeded"
+	EventTypeTestHelpersTestClockAdvancing                      = "test_helpers.test_clock.advancing"
+	EventTypeTestHelpersTestClockCreated                        = "test_helpers.test_clock.created"
+	EventTypeTestHelpersTestClockDeleted                        = "test_helpers.test_clock.deleted"
+	EventTypeTestHelpersTestClockInternalFailure                = "test_helpers.test_clock.internal_failure"
+	EventTypeTestHelpersTestClockReady                          = "test_helpers.test_clock.ready"
+	EventTypeTopupCanceled                                      = "topup.canceled"
+	EventTypeTopupCreated                                       = "topup.created"
+	EventTypeTopupFailed                                        = "topup.failed"
+	EventTypeTopupReversed                                      = "topup.reversed"
+	EventTypeTopupSucceeded                                     = "topup.succeeded"
+	EventTypeTransferCreated                                    = "transfer.created"
+	EventTypeTransferReversed                                   = "transfer.reversed"
+	EventTypeTransferUpdated                                    = "transfer.updated"
+	EventTypeTreasuryCreditReversalCreated                      = "treasury.credit_reversal.created"
+	EventTypeTreasuryCreditReversalPosted                       = "treasury.credit_reversal.posted"
+	EventTypeTreasuryDebitReversalCompleted                     = "treasury.debit_reversal.completed"
+	EventTypeTreasuryDebitReversalCreated                       = "treasury.debit_reversal.created"
+	EventTypeTreasuryDebitReversalInitialCreditGranted          = "treasury.debit_reversal.initial_credit_granted"
+	EventTypeTreasuryFinancialAccountClosed                     = "treasury.financial_account.closed"
+	EventTypeTreasuryFinancialAccountCreated                    = "treasury.financial_account.created"
+	EventTypeTreasuryFinancialAccountFeaturesStatusUpdated      = "treasury.financial_account.features_status_updated"
+	EventTypeTreasuryInboundTransferCanceled                    = "treasury.inbound_transfer.canceled"
+	EventTypeTreasuryInboundTransferCreated                     = "treasury.inbound_transfer.created"
+	EventTypeTreasuryInboundTransferFailed                      = "treasury.inbound_transfer.failed"
+	EventTypeTreasuryInboundTransferSucceeded                   = "treasury.inbound_transfer.succeeded"
+	EventTypeTreasuryOutboundPaymentCanceled                    = "treasury.outbound_payment.canceled"
+	EventTypeTreasuryOutboundPaymentCreated                     = "treasury.outbound_payment.created"
+	EventTypeTreasuryOutboundPaymentExpectedArrivalDateUpdated  = "treasury.outbound_payment.expected_arrival_date_updated"
+	EventTypeTreasuryOutboundPaymentFailed                      = "treasury.outbound_payment.failed"
+	EventTypeTreasuryOutboundPaymentPosted                      = "treasury.outbound_payment.posted"
+	EventTypeTreasuryOutboundPaymentReturned                    = "treasury.outbound_payment.returned"
+	EventTypeTreasuryOutboundTransferCanceled                   = "treasury.outbound_transfer.canceled"
+	EventTypeTreasuryOutboundTransferCreated                    = "treasury.outbound_transfer.created"
+	EventTypeTreasuryOutboundTransferExpectedArrivalDateUpdated = "treasury.outbound_transfer.expected_arrival_date_updated"
+	EventTypeTreasuryOutboundTransferFailed                     = "treasury.outbound_transfer.failed"
+	EventTypeTreasuryOutboundTransferPosted                     = "treasury.outbound_transfer.posted"
+	EventTypeTreasuryOutboundTransferReturned                   = "treasury.outbound_transfer.returned"
+	EventTypeTreasuryReceivedCreditCreated                      = "treasury.received_credit.created"
+	EventTypeTreasuryReceivedCreditFailed                       = "treasury.received_credit.failed"
+	EventTypeTreasuryReceivedCreditSucceeded                    = "treasury.received_credit.succeeded"
+	EventTypeTreasuryReceivedDebitCreated                       = "treasury.received_debit.created"
+	EventTypeInvoiceItemUpdated                                 = "invoiceitem.updated"
+	EventTypeOrderCreated                                       = "order.created"
+	EventTypeRecipientCreated                                   = "recipient.created"
+	EventTypeRecipientDeleted                                   = "recipient.deleted"
+	EventTypeRecipientUpdated                                   = "recipient.updated"
+	EventTypeSKUCreated                                         = "sku.created"
+	EventTypeSKUDeleted                                         = "sku.deleted"
+	EventTypeSKUUpdated                                         = "sku.updated"
+)
+
+var knownEventTypes = map[EventType]struct{}{
+	EventTypeAccountApplicationAuthorized:                       {},
+	EventTypeAccountApplicationDeauthorized:                     {},
+	EventTypeAccountExternalAccountCreated:                      {},
+	EventTypeAccountExternalAccountDeleted:                      {},
+	EventTypeAccountExternalAccountUpdated:                      {},
+	EventTypeAccountUpdated:                                     {},
+	EventTypeApplicationFeeCreated:                              {},
+	EventTypeApplicationFeeRefundUpdated:                        {},
+	EventTypeApplicationFeeRefunded:                             {},
+	EventTypeBalanceAvailable:                                   {},
+	EventTypeBillingPortalConfigurationCreated:                  {},
+	EventTypeBillingPortalConfigurationUpdated:                  {},
+	EventTypeBillingPortalSessionCreated:                        {},
+	EventTypeCapabilityUpdated:                                  {},
+	EventTypeCashBalanceFundsAvailable:                          {},
+	EventTypeChargeCaptured:                                     {},
+	EventTypeChargeDisputeClosed:                                {},
+	EventTypeChargeDisputeCreated:                               {},
+	EventTypeChargeDisputeFundsReinstated:                       {},
+	EventTypeChargeDisputeFundsWithdrawn:                        {},
+	EventTypeChargeDisputeUpdated:                               {},
+	EventTypeChargeExpired:                                      {},
+	EventTypeChargeFailed:                                       {},
+	EventTypeChargePending:                                      {},
+	EventTypeChargeRefundUpdated:                                {},
+	EventTypeChargeRefunded:                                     {},
+	EventTypeChargeSucceeded:                                    {},
+	EventTypeChargeUpdated:                                      {},
+	EventTypeCheckoutSessionAsyncPaymentFailed:                  {},
+	EventTypeCheckoutSessionAsyncPaymentSucceeded:               {},
+	EventTypeCheckoutSessionCompleted:                           {},
+	EventTypeCheckoutSessionExpired:                             {},
+	EventTypeClimateOrderCanceled:                               {},
+	EventTypeClimateOrderCreated:                                {},
+	EventTypeClimateOrderDelayed:                                {},
+	EventTypeClimateOrderDelivered:                              {},
+	EventTypeClimateOrderProductSubstituted:                     {},
+	EventTypeClimateProductCreated:                              {},
+	EventTypeClimateProductPricingUpdated:                       {},
+	EventTypeCouponCreated:                                      {},
+	EventTypeCouponDeleted:                                      {},
+	EventTypeCouponUpdated:                                      {},
+	EventTypeCreditNoteCreated:                                  {},
+	EventTypeCreditNoteUpdated:                                  {},
+	EventTypeCreditNoteVoided:                                   {},
+	EventTypeCustomerCreated:                                    {},
+	EventTypeCustomerDeleted:                                    {},
+	EventTypeCustomerDiscountCreated:                            {},
+	EventTypeCustomerDiscountDeleted:                            {},
+	EventTypeCustomerDiscountUpdated:                            {},
+	EventTypeCustomerSourceCreated:                              {},
+	EventTypeCustomerSourceDeleted:                              {},
+	EventTypeCustomerSourceExpiring:                             {},
+	EventTypeCustomerSourceUpdated:                              {},
+	EventTypeCustomerSubscriptionCreated:                        {},
+	EventTypeCustomerSubscriptionDeleted:                        {},
+	EventTypeCustomerSubscriptionPaused:                         {},
+	EventTypeCustomerSubscriptionPendingUpdateApplied:           {},
+	EventTypeCustomerSubscriptionPendingUpdateExpired:           {},
+	EventTypeCustomerSubscriptionResumed:                        {},
+	EventTypeCustomerSubscriptionTrialWillEnd:                   {},
+	EventTypeCustomerSubscriptionUpdated:                        {},
+	EventTypeCustomerTaxIDCreated:                               {},
+	EventTypeCustomerTaxIDDeleted:                               {},
+	EventTypeCustomerTaxIDUpdated:                               {},
+	EventTypeCustomerUpdated:                                    {},
+	EventTypeCustomerCashBalanceTransactionCreated:              {},
+	EventTypeFileCreated:                                        {},
+	EventTypeFinancialConnectionsAccountCreated:                 {},
+	EventTypeFinancialConnectionsAccountDeactivated:             {},
+	EventTypeFinancialConnectionsAccountDisconnected:            {},
+	EventTypeFinancialConnectionsAccountReactivated:             {},
+	EventTypeFinancialConnectionsAccountRefreshedBalance:        {},
+	EventTypeFinancialConnectionsAccountRefreshedOwnership:      {},
+	EventTypeFinancialConnectionsAccountRefreshedTransactions:   {},
+	EventTypeIdentityVerificationSessionCanceled:                {},
+	EventTypeIdentityVerificationSessionCreated:                 {},
+	EventTypeIdentityVerificationSessionProcessing:              {},
+	EventTypeIdentityVerificationSessionRedacted:                {},
+	EventTypeIdentityVerificationSessionRequiresInput:           {},
+	EventTypeIdentityVerificationSessionVerified:                {},
+	EventTypeInvoiceCreated:                                     {},
+	EventTypeInvoiceDeleted:                                     {},
+	EventTypeInvoiceFinalizationFailed:                          {},
+	EventTypeInvoiceFinalized:                                   {},
+	EventTypeInvoiceMarkedUncollectible:                         {},
+	EventTypeInvoicePaid:                                        {},
+	EventTypeInvoicePaymentActionRequired:                       {},
+	EventTypeInvoicePaymentFailed:                               {},
+	EventTypeInvoicePaymentSucceeded:                            {},
+	EventTypeInvoiceSent:                                        {},
+	EventTypeInvoiceUpcoming:                                    {},
+	EventTypeInvoiceUpdated:                                     {},
+	EventTypeInvoiceVoided:                                      {},
+	EventTypeInvoiceItemCreated:                                 {},
+	EventTypeInvoiceItemDeleted:                                 {},
+	EventTypeIssuingAuthorizationCreated:                        {},
+	EventTypeIssuingAuthorizationRequest:                        {},
+	EventTypeIssuingAuthorizationUpdated:                        {},
+	EventTypeIssuingCardCreated:                                 {},
+	EventTypeIssuingCardUpdated:                                 {},
+	EventTypeIssuingCardholderCreated:                           {},
+	EventTypeIssuingCardholderUpdated:                           {},
+	EventTypeIssuingDisputeClosed:                               {},
+	EventTypeIssuingDisputeCreated:                              {},
+	EventTypeIssuingDisputeFundsReinstated:                      {},
+	EventTypeIssuingDisputeSubmitted:                            {},
+	EventTypeIssuingDisputeUpdated:                              {},
+	EventTypeIssuingTokenCreated:                                {},
+	EventTypeIssuingTokenUpdated:                                {},
+	EventTypeIssuingTransactionCreated:                          {},
+	EventTypeIssuingTransactionUpdated:                          {},
+	EventTypeMandateUpdated:                                     {},
+	EventTypePaymentIntentAmountCapturableUpdated:               {},
+	EventTypePaymentIntentCanceled:                              {},
+	EventTypePaymentIntentCreated:                               {},
+	EventTypePaymentIntentPartiallyFunded:                       {},
+	EventTypePaymentIntentPaymentFailed:                         {},
+	EventTypePaymentIntentProcessing:                            {},
+	EventTypePaymentIntentRequiresAction:                        {},
+	EventTypePaymentIntentSucceeded:                             {},
+	EventTypePaymentLinkCreated:                                 {},
+	EventTypePaymentLinkUpdated:                                 {},
+	EventTypePaymentMethodAttached:                              {},
+	EventTypePaymentMethodAutomaticallyUpdated:                  {},
+	EventTypePaymentMethodDetached:                              {},
+	EventTypePaymentMethodUpdated:                               {},
+	EventTypePayoutCanceled:                                     {},
+	EventTypePayoutCreated:                                      {},
+	EventTypePayoutFailed:                                       {},
+	EventTypePayoutPaid:                                         {},
+	EventTypePayoutReconciliationCompleted:                      {},
+	EventTypePayoutUpdated:                                      {},
+	EventTypePersonCreated:                                      {},
+	EventTypePersonDeleted:                                      {},
+	EventTypePersonUpdated:                                      {},
+	EventTypePlanCreated:                                        {},
+	EventTypePlanDeleted:                                        {},
+	EventTypePlanUpdated:                                        {},
+	EventTypePriceCreated:                                       {},
+	EventTypePriceDeleted:                                       {},
+	EventTypePriceUpdated:                                       {},
+	EventTypeProductCreated:                                     {},
+	EventTypeProductDeleted:                                     {},
+	EventTypeProductUpdated:                                     {},
+	EventTypePromotionCodeCreated:                               {},
+	EventTypePromotionCodeUpdated:                               {},
+	EventTypeQuoteAccepted:                                      {},
+	EventTypeQuoteCanceled:                                      {},
+	EventTypeQuoteCreated:                                       {},
+	EventTypeQuoteFinalized:                                     {},
+	EventTypeRadarEarlyFraudWarningCreated:                      {},
+	EventTypeRadarEarlyFraudWarningUpdated:                      {},
+	EventTypeRefundCreated:                                      {},
+	EventTypeRefundUpdated:                                      {},
+	EventTypeReportingReportRunFailed:                           {},
+	EventTypeReportingReportRunSucceeded:                        {},
+	EventTypeReportingReportTypeUpdated:                         {},
+	EventTypeReviewClosed:                                       {},
+	EventTypeReviewOpened:                                       {},
+	EventTypeSetupIntentCanceled:                                {},
+	EventTypeSetupIntentCreated:                                 {},
+	EventTypeSetupIntentRequiresAction:                          {},
+	EventTypeSetupIntentSetupFailed:                             {},
+	EventTypeSetupIntentSucceeded:                               {},
+	EventTypeSigmaScheduledQueryRunCreated:                      {},
+	EventTypeSourceCanceled:                                     {},
+	EventTypeSourceChargeable:                                   {},
+	EventTypeSourceFailed:                                       {},
+	EventTypeSourceMandateNotification:                          {},
+	EventTypeSourceRefundAttributesRequired:                     {},
+	EventTypeSourceTransactionCreated:                           {},
+	EventTypeSourceTransactionUpdated:                           {},
+	EventTypeSubscriptionScheduleAborted:                        {},
+	EventTypeSubscriptionScheduleCanceled:                       {},
+	EventTypeSubscriptionScheduleCompleted:                      {},
+	EventTypeSubscriptionScheduleCreated:                        {},
+	EventTypeSubscriptionScheduleExpiring:                       {},
+	EventTypeSubscriptionScheduleReleased:                       {},
+	EventTypeSubscriptionScheduleUpdated:                        {},
+	EventTypeTaxSettingsUpdated:                                 {},
+	EventTypeTaxRateCreated:                                     {},
+	EventTypeTaxRateUpdated:                                     {},
+	EventTypeTerminalReaderActionFailed:                         {},
+	EventTypeTerminalReaderActionSucceeded:                      {},
+	EventTypeTestHelpersTestClockAdvancing:                      {},
+	EventTypeTestHelpersTestClockCreated:                        {},
+	EventTypeTestHelpersTestClockDeleted:                        {},
+	EventTypeTestHelpersTestClockInternalFailure:                {},
+	EventTypeTestHelpersTestClockReady:                          {},
+	EventTypeTopupCanceled:                                      {},
+	EventTypeTopupCreated:                                       {},
+	EventTypeTopupFailed:                                        {},
+	EventTypeTopupReversed:                                      {},
+	EventTypeTopupSucceeded:                                     {},
+	EventTypeTransferCreated:                                    {},
+	EventTypeTransferReversed:                                   {},
+	EventTypeTransferUpdated:                                    {},
+	EventTypeTreasuryCreditReversalCreated:                      {},
+	EventTypeTreasuryCreditReversalPosted:                       {},
+	EventTypeTreasuryDebitReversalCompleted:                     {},
+	EventTypeTreasuryDebitReversalCreated:                       {},
+	EventTypeTreasuryDebitReversalInitialCreditGranted:          {},
+	EventTypeTreasuryFinancialAccountClosed:                     {},
+	EventTypeTreasuryFinancialAccountCreated:                    {},
+	EventTypeTreasuryFinancialAccountFeaturesStatusUpdated:      {},
+	EventTypeTreasuryInboundTransferCanceled:                    {},
+	EventTypeTreasuryInboundTransferCreated:                     {},
+	EventTypeTreasuryInboundTransferFailed:                      {},
+	EventTypeTreasuryInboundTransferSucceeded:                   {},
+	EventTypeTreasuryOutboundPaymentCanceled:                    {},
+	EventTypeTreasuryOutboundPaymentCreated:                     {},
+	EventTypeTreasuryOutboundPaymentExpectedArrivalDateUpdated:  {},
+	EventTypeTreasuryOutboundPaymentFailed:                      {},
+	EventTypeTreasuryOutboundPaymentPosted:                      {},
+	EventTypeTreasuryOutboundPaymentReturned:                    {},
+	EventTypeTreasuryOutboundTransferCanceled:                   {},
+	EventTypeTreasuryOutboundTransferCreated:                    {},
+	EventTypeTreasuryOutboundTransferExpectedArrivalDateUpdated: {},
+	EventTypeTreasuryOutboundTransferFailed:                     {},
+	EventTypeTreasuryOutboundTransferPosted:                     {},
+	EventTypeTreasuryOutboundTransferReturned:                   {},
+	EventTypeTreasuryReceivedCreditCreated:                      {},
+	EventTypeTreasuryReceivedCreditFailed:                       {},
+	EventTypeTreasuryReceivedCreditSucceeded:                    {},
+	EventTypeTreasuryReceivedDebitCreated:                       {},
+	EventTypeInvoiceItemUpdated:                                 {},
+	EventTypeOrderCreated:                                       {},
+	EventTypeRecipientCreated:                                   {},
+	EventTypeRecipientDeleted:                                   {},
+	EventTypeRecipientUpdated:                                   {},
+	EventTypeSKUCreated:                                         {},
+	EventTypeSKUDeleted:                                         {},
+	EventTypeSKUUpdated:                                         {},
+}
@@ -0,0 +1,118 @@
+package stripetotrello
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	stripe "github.com/stripe/stripe-go/v76"
+)
+
+type (
+	fetchedObject struct {
+		stripe.APIResource
+	}
+
+	// fetchParams follows the stripe-go params, passing *stripe.Params
+	// directly trips its check against the deprecated Params.Expand.
+	fetchParams struct {
+		stripe.Params `form:"*"`
+		Expand        []*string `form:"expand"`
+	}
+)
+
+// objectPaths maps the object types FetchObject supports to their API path.
+var objectPaths = map[string]string{
+	"charge":           "/v1/charges",
+	"checkout.session": "/v1/checkout/sessions",
+	"customer":         "/v1/customers",
+	"dispute":          "/v1/disputes",
+	"invoice":          "/v1/invoices",
+	"invoiceitem":      "/v1/invoiceitems",
+	"payment_intent":   "/v1/payment_intents",
+	"payment_method":   "/v1/payment_methods",
+	"payout":           "/v1/payouts",
+	"price":            "/v1/prices",
+	"product":          "/v1/products",
+	"refund":           "/v1/refunds",
+	"setup_intent":     "/v1/setup_intents",
+	"subscription":     "/v1/subscriptions",
+}
+
+// WithStripeAPIKey sets the secret key FetchObject calls the Stripe API with.
+func WithStripeAPIKey(key string) func(*Client) {
+	return func(c *Client) {
+		c.stripeAPIKey = key
+	}
+}
+
+// WithExpand makes FetchObject expand fields, e.g. "customer", for every
+// event type without expansions of its own.
+func WithExpand(fields ...string) func(*Client) {
+	return WithExpandFor(WILDCARD, fields...)
+}
+
+// WithExpandFor sets the fields FetchObject expands for an event type or a
+// pattern, matched like AppendHandler ones.
+func WithExpandFor(eventType string, fields ...string) func(*Client) {
+	return func(c *Client) {
+		if c.expand == nil {
+			c.expand = make(map[string][]string)
+		}
+		c.expand[eventType] = append(c.expand[eventType], fields...)
+	}
+}
+
+// WithStripeBackend replaces the stripe-go backend used by FetchObject, the
+// default API backend otherwise.
+func WithStripeBackend(b stripe.Backend) func(*Client) {
+	return func(c *Client) {
+		c.stripeBackend = b
+	}
+}
+
+// FetchObject retrieves the current version of the object the event is about
+// from the Stripe API and decodes it into dest, for events whose payload
+// only carries the object id such as the ones bridged by ThinEvent.
+func (st *Client) FetchObject(ctx context.Context, event *stripe.Event, dest interface{}) error {
+	if st.stripeAPIKey == "" {
+		return newError("Client.FetchObject", []interface{}{event}, ErrNoStripeAPIKey)
+	}
+
+	var object, id string
+	if event.Data != nil {
+		object, _ = event.Data.Object["object"].(string)
+		id, _ = event.Data.Object["id"].(string)
+	}
+	path, ok := objectPaths[object]
+	if ok {
+		path = fmt.Sprintf("%s/%s", path, id)
+	} else if event.Data != nil {
+		// Thin events reference objects by url, including the v2 ones
+		// objectPaths does not know about.
+		path, ok = thinObjectPath(event.Data.Object)
+	}
+	if !ok || id == "" {
+		return newError("Client.FetchObject", []interface{}{event, object}, ErrUnsupportedObject)
+	}
+
+	backend := st.stripeBackend
+	if backend == nil {
+		backend = stripe.GetBackend(stripe.APIBackend)
+	}
+
+	params := &fetchParams{Params: stripe.Params{Context: ctx}}
+	fields, _ := match(st.expand, string(event.Type))
+	for _, field := range fields {
+		params.Expand = append(params.Expand, stripe.String(field))
+	}
+	var fetched fetchedObject
+	if err := backend.Call(http.MethodGet, path, st.stripeAPIKey, params, &fetched); err != nil {
+		return newError("Client.FetchObject", []interface{}{event, object, id}, err)
+	}
+	if err := json.Unmarshal(fetched.LastResponse.RawJSON, dest); err != nil {
+		return newError("Client.FetchObject", []interface{}{event, object, id}, err)
+	}
+	return nil
+}
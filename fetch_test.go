@@ -0,0 +1,128 @@
+package stripetotrello
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	stripe "github.com/stripe/stripe-go/v76"
+)
+
+func stripeBackend(t *testing.T, handler http.HandlerFunc) stripe.Backend {
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	return stripe.GetBackendWithConfig(stripe.APIBackend, &stripe.BackendConfig{
+		URL:               stripe.String(srv.URL),
+		MaxNetworkRetries: stripe.Int64(0),
+		LeveledLogger:     &stripe.LeveledLogger{Level: stripe.LevelNull},
+	})
+}
+
+func TestFetchObject(t *testing.T) {
+	var paths, keys []string
+	backend := stripeBackend(t, func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		keys = append(keys, r.Header.Get("Authorization"))
+		fmt.Fprint(w, `{"id": "in_123", "object": "invoice", "customer_email": "jane@example.com"}`)
+	})
+
+	type testCase struct {
+		name     string
+		cfgs     []func(*Client)
+		object   map[string]interface{}
+		sentinel error
+	}
+
+	tcs := []testCase{
+		{"invoice", []func(*Client){WithStripeAPIKey("sk_test_123")}, map[string]interface{}{"object": "invoice", "id": "in_123"}, nil},
+		{"no api key", nil, map[string]interface{}{"object": "invoice", "id": "in_123"}, ErrNoStripeAPIKey},
+		{"unsupported object", []func(*Client){WithStripeAPIKey("sk_test_123")}, map[string]interface{}{"object": "balance"}, ErrUnsupportedObject},
+	}
+
+	for _, tc := range tcs {
+		paths, keys = nil, nil
+		client := NewClient(append(tc.cfgs, WithStripeBackend(backend))...)
+		event := &stripe.Event{Type: "invoice.paid", Data: &stripe.EventData{Object: tc.object}}
+
+		var invoice stripe.Invoice
+		err := client.FetchObject(context.Background(), event, &invoice)
+		if tc.sentinel != nil {
+			if !errors.Is(err, tc.sentinel) {
+				t.Errorf("Expected %s to fail with %s, got %v", tc.name, tc.sentinel, err)
+			}
+			if len(paths) != 0 {
+				t.Errorf("Expected %s to NOT call the API", tc.name)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Fatalf("Expected %s to NOT fail, got %s", tc.name, err)
+		}
+		if len(paths) != 1 || paths[0] != "/v1/invoices/in_123" || keys[0] != "Bearer sk_test_123" {
+			t.Errorf("Expected %s to GET /v1/invoices/in_123 with the api key, got %v %v", tc.name, paths, keys)
+		}
+		if invoice.ID != "in_123" || invoice.CustomerEmail != "jane@example.com" {
+			t.Errorf("Expected %s to decode the fetched invoice, got %+v", tc.name, invoice)
+		}
+	}
+}
+
+func TestFetchObjectExpand(t *testing.T) {
+	var expands [][]string
+	backend := stripeBackend(t, func(w http.ResponseWriter, r *http.Request) {
+		expands = append(expands, expandParams(r))
+		fmt.Fprint(w, `{"id": "in_123", "object": "invoice", "customer": {"id": "cus_123", "object": "customer", "email": "jane@example.com"}}`)
+	})
+
+	type testCase struct {
+		event  string
+		expand []string
+	}
+
+	tcs := []testCase{
+		{"invoice.paid", []string{"customer", "subscription"}},
+		{"invoice.created", []string{"customer"}},
+		{"charge.succeeded", []string{"balance_transaction"}},
+	}
+
+	client := NewClient(
+		WithStripeAPIKey("sk_test_123"),
+		WithStripeBackend(backend),
+		WithExpand("balance_transaction"),
+		WithExpandFor("invoice.*", "customer"),
+		WithExpandFor("invoice.paid", "customer", "subscription"),
+	)
+
+	for _, tc := range tcs {
+		expands = nil
+		event := &stripe.Event{Type: stripe.EventType(tc.event), Data: &stripe.EventData{Object: map[string]interface{}{"object": "invoice", "id": "in_123"}}}
+
+		var invoice stripe.Invoice
+		if err := client.FetchObject(context.Background(), event, &invoice); err != nil {
+			t.Fatalf("Expected %s to NOT fail, got %s", tc.event, err)
+		}
+		if len(expands) != 1 || strings.Join(expands[0], ",") != strings.Join(tc.expand, ",") {
+			t.Errorf("Expected %s to expand %v, got %v", tc.event, tc.expand, expands)
+		}
+		if invoice.Customer == nil || invoice.Customer.Email != "jane@example.com" {
+			t.Errorf("Expected %s to decode the expanded customer, got %+v", tc.event, invoice.Customer)
+		}
+	}
+}
+
+func expandParams(r *http.Request) []string {
+	var fields []string
+	for i := 0; ; i++ {
+		field := r.URL.Query().Get(fmt.Sprintf("expand[%d]", i))
+		if field == "" {
+			return fields
+		}
+		fields = append(fields, field)
+	}
+}
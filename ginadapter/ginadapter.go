@@ -0,0 +1,24 @@
+package ginadapter
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/skipper-digital-studio/stripetotrello"
+)
+
+// Middleware verifies and dispatches the webhook with Client.HandleRequest,
+// the handlers get the request context. It aborts with the status the client
+// maps an error to, otherwise it sets that status and calls the next handlers
+// with the body restored.
+func Middleware(c *stripetotrello.Client) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		status, _ := c.HandleRequest(ctx.Request)
+		if status >= http.StatusBadRequest {
+			ctx.AbortWithStatus(status)
+			return
+		}
+		ctx.Status(status)
+		ctx.Next()
+	}
+}
@@ -0,0 +1,98 @@
+package ginadapter
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/skipper-digital-studio/stripetotrello"
+	"github.com/skipper-digital-studio/stripetotrello/testutil"
+	stripe "github.com/stripe/stripe-go/v76"
+)
+
+const testSecret = "whsec_test"
+
+func TestMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	type testCase struct {
+		name   string
+		secret string
+		event  string
+		status int
+	}
+
+	client := stripetotrello.NewClient(stripetotrello.WithStripeWebhookSecret(testSecret), stripetotrello.WithMaxBodyBytes(256))
+	client.AppendHandler("customer.created", func(_ *stripe.Event) (stripetotrello.EventResponse, error) {
+		return "ok", nil
+	})
+	client.AppendHandler("customer.deleted", func(_ *stripe.Event) (stripetotrello.EventResponse, error) {
+		return nil, fmt.Errorf("test")
+	})
+
+	var downstream []byte
+	router := gin.New()
+	router.POST("/webhook", Middleware(client), func(ctx *gin.Context) {
+		downstream, _ = io.ReadAll(ctx.Request.Body)
+	})
+
+	tcs := []testCase{
+		{"success", testSecret, "customer.created", http.StatusOK},
+		{"invalid signature", "whsec_other", "customer.created", http.StatusBadRequest},
+		{"handler failure", testSecret, "customer.deleted", http.StatusInternalServerError},
+		{"unhandled event type", testSecret, "invoice.paid", http.StatusOK},
+		{"too large", testSecret, "customer.created" + strings.Repeat(" ", 256), http.StatusRequestEntityTooLarge},
+	}
+
+	for _, tc := range tcs {
+		downstream = nil
+		payload := testutil.Payload(tc.event)
+		req := httptest.NewRequest(http.MethodPost, "/webhook", bytes.NewReader(payload))
+		req.Header.Set(stripetotrello.SIGNATURE_HEADER, testutil.SignPayload(tc.secret, payload, time.Now()))
+
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		if rec.Code != tc.status {
+			t.Errorf("Expected status %d for %s, got %d", tc.status, tc.name, rec.Code)
+		}
+		if passed := downstream != nil; passed != (tc.status < http.StatusBadRequest) {
+			t.Errorf("Expected %s to reach the downstream handler = %t", tc.name, !passed)
+		}
+		if passed := downstream != nil; passed && !bytes.Equal(downstream, payload) {
+			t.Errorf("Expected the body to be restored for %s, got %q", tc.name, downstream)
+		}
+	}
+}
+
+type requestKey struct{}
+
+func TestMiddlewareContext(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	client := stripetotrello.NewClient(stripetotrello.WithStripeWebhookSecret(testSecret))
+	var value interface{}
+	client.AppendHandlerCtx("customer.created", func(ctx context.Context, _ *stripe.Event) (stripetotrello.EventResponse, error) {
+		value = ctx.Value(requestKey{})
+		return nil, nil
+	})
+
+	router := gin.New()
+	router.POST("/webhook", Middleware(client))
+
+	payload := testutil.Payload("customer.created")
+	req := httptest.NewRequest(http.MethodPost, "/webhook", bytes.NewReader(payload))
+	req.Header.Set(stripetotrello.SIGNATURE_HEADER, testutil.SignPayload(testSecret, payload, time.Now()))
+	router.ServeHTTP(httptest.NewRecorder(), req.WithContext(context.WithValue(req.Context(), requestKey{}, "request")))
+
+	if value != "request" {
+		t.Errorf("Expected the handlers to get the request context, got %v", value)
+	}
+}
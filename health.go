@@ -0,0 +1,28 @@
+package stripetotrello
+
+import (
+	"fmt"
+)
+
+// Healthy reports whether the client can process events: it is not closed,
+// can verify events, has at least one handler and the circuit breaker, if
+// any, is not open for every event type it saw.
+func (st *Client) Healthy() error {
+	st.lifecycle.Lock()
+	closed := st.closed
+	st.lifecycle.Unlock()
+	if closed {
+		return newError("Client.Healthy", nil, ErrClientClosed)
+	}
+
+	if err := st.Validate(); err != nil {
+		return newError("Client.Healthy", nil, err)
+	}
+	if len(st.RegisteredEventTypes()) == 0 {
+		return newError("Client.Healthy", nil, fmt.Errorf("%w: the client has no handlers", ErrNoHandler))
+	}
+	if st.breaker.allOpen() {
+		return newError("Client.Healthy", nil, fmt.Errorf("%w for every event type", ErrCircuitOpen))
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package stripetotrello
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	stripe "github.com/stripe/stripe-go/v76"
+)
+
+func TestHealthy(t *testing.T) {
+	failing := func(_ *stripe.Event) (EventResponse, error) {
+		return nil, errors.New("trello unavailable")
+	}
+
+	type testCase struct {
+		name     string
+		client   func() *Client
+		sentinel error
+	}
+
+	tcs := []testCase{
+		{"healthy", func() *Client {
+			c := NewClient(WithStripeWebhookSecret(testSecret))
+			c.AppendHandler("customer.created", failing)
+			return c
+		}, nil},
+		{"missing secret", func() *Client {
+			c := NewClient()
+			c.AppendHandler("customer.created", failing)
+			return c
+		}, ErrNoWebhookSecret},
+		{"no handler", func() *Client {
+			return NewClient(WithStripeWebhookSecret(testSecret))
+		}, ErrNoHandler},
+		{"closed", func() *Client {
+			c := NewClient(WithStripeWebhookSecret(testSecret))
+			c.AppendHandler("customer.created", failing)
+			c.Close(context.Background())
+			return c
+		}, ErrClientClosed},
+		{"partly open breaker", func() *Client {
+			c := NewClient(WithStripeWebhookSecret(testSecret), WithCircuitBreaker(1, time.Minute))
+			c.AppendHandler("customer.created", failing)
+			c.AppendHandler("customer.updated", func(_ *stripe.Event) (EventResponse, error) { return "ok", nil })
+			c.Handle(&stripe.Event{Type: "customer.created"})
+			c.Handle(&stripe.Event{Type: "customer.updated"})
+			return c
+		}, nil},
+		{"open breaker", func() *Client {
+			c := NewClient(WithStripeWebhookSecret(testSecret), WithCircuitBreaker(1, time.Minute))
+			c.AppendHandler("customer.created", failing)
+			c.Handle(&stripe.Event{Type: "customer.created"})
+			return c
+		}, ErrCircuitOpen},
+	}
+
+	for _, tc := range tcs {
+		err := tc.client().Healthy()
+		if tc.sentinel == nil && err != nil {
+			t.Errorf("Expected %s to be healthy, got %s", tc.name, err)
+		}
+		if tc.sentinel != nil && !errors.Is(err, tc.sentinel) {
+			t.Errorf("Expected %s to be unhealthy with %s, got %v", tc.name, tc.sentinel, err)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package stripetotrello
+
+import (
+	"context"
+
+	stripe "github.com/stripe/stripe-go/v76"
+)
+
+type (
+	// BeforeHandlerHook and AfterHandlerHook observe every handler call in
+	// Handle and HandleParallel, index is the handler position for the event.
+	// They cannot change the outcome, the after hook sees it once the retries
+	// are done.
+	BeforeHandlerHook func(ctx context.Context, event *stripe.Event, index int)
+	AfterHandlerHook  func(ctx context.Context, event *stripe.Event, index int, res EventResponse, err error)
+)
+
+// WithBeforeHandler adds a hook run before each handler, hooks run in the
+// order they were added.
+func WithBeforeHandler(hook BeforeHandlerHook) func(*Client) {
+	return func(c *Client) {
+		if hook != nil {
+			c.beforeHandler = append(c.beforeHandler, hook)
+		}
+	}
+}
+
+func WithAfterHandler(hook AfterHandlerHook) func(*Client) {
+	return func(c *Client) {
+		if hook != nil {
+			c.afterHandler = append(c.afterHandler, hook)
+		}
+	}
+}
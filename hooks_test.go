@@ -0,0 +1,69 @@
+package stripetotrello
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+
+	stripe "github.com/stripe/stripe-go/v76"
+)
+
+func TestHandlerHooks(t *testing.T) {
+	type call struct {
+		hook  string
+		index int
+		res   EventResponse
+		err   error
+	}
+
+	cause := errors.New("trello unavailable")
+	var (
+		mu    sync.Mutex
+		calls []call
+	)
+	client := NewClient(WithContinueOnError(),
+		WithBeforeHandler(func(_ context.Context, e *stripe.Event, i int) {
+			mu.Lock()
+			defer mu.Unlock()
+			calls = append(calls, call{hook: "before " + e.ID, index: i})
+		}),
+		WithAfterHandler(func(_ context.Context, e *stripe.Event, i int, res EventResponse, err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			calls = append(calls, call{hook: "after " + e.ID, index: i, res: res, err: err})
+		}),
+	)
+	client.AppendHandler("customer.created", func(_ *stripe.Event) (EventResponse, error) {
+		return "first", nil
+	}, func(_ *stripe.Event) (EventResponse, error) {
+		return nil, cause
+	})
+
+	for name, handle := range map[string]func(*stripe.Event) error{"Handle": client.Handle, "HandleParallel": client.HandleParallel} {
+		calls = nil
+		handle(&stripe.Event{ID: "evt_1", Type: "customer.created"})
+
+		if name == "Handle" && (len(calls) != 4 || calls[0].hook != "before evt_1" || calls[1].hook != "after evt_1") {
+			t.Errorf("Expected Handle to run the hooks around each handler, got %v", calls)
+		}
+
+		var after []call
+		for _, c := range calls {
+			if c.hook == "after evt_1" {
+				after = append(after, c)
+			}
+		}
+		sort.Slice(after, func(i, j int) bool { return after[i].index < after[j].index })
+		if len(calls) != 4 || len(after) != 2 {
+			t.Fatalf("Expected %s to fire both hooks for both handlers, got %v", name, calls)
+		}
+		if after[0].index != 0 || after[0].res != "first" || after[0].err != nil {
+			t.Errorf("Expected %s to pass the first handler outcome, got %v", name, after[0])
+		}
+		if after[1].index != 1 || !errors.Is(after[1].err, cause) {
+			t.Errorf("Expected %s to pass the second handler error, got %v", name, after[1])
+		}
+	}
+}
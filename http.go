@@ -0,0 +1,131 @@
+package stripetotrello
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const (
+	SIGNATURE_HEADER = "Stripe-Signature"
+	MAX_BODY_BYTES   = int64(1 << 20)
+)
+
+// ServeHTTP verifies and dispatches the webhook request, the handlers get the
+// request context.
+func (st *Client) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	r, status, err := st.serve(w, r)
+	st.respond(w, r, status, err)
+}
+
+// HandleRequest verifies and dispatches the webhook request like ServeHTTP,
+// honouring WithMaxBodyBytes, WithSignatureHeader, the SecretFunc and the
+// status mapper, and returns the status ServeHTTP would answer with and the
+// error it is mapped from. The body is restored for the next handlers, it is
+// for adapters to other routers.
+func (st *Client) HandleRequest(r *http.Request) (int, error) {
+	_, status, err := st.serve(nil, r)
+	return status, err
+}
+
+func (st *Client) serve(w http.ResponseWriter, r *http.Request) (*http.Request, int, error) {
+	raw, err := io.ReadAll(http.MaxBytesReader(w, r.Body, st.maxBodyBytes))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return r, http.StatusRequestEntityTooLarge, err
+		}
+		return r, http.StatusBadRequest, err
+	}
+	r.Body = io.NopCloser(bytes.NewReader(raw))
+
+	event, err := st.requestEvent(r, raw)
+	if err == nil {
+		r = r.WithContext(context.WithValue(r.Context(), requestEventKey{}, event.Event))
+		err = st.HandleRawEventContext(r.Context(), event)
+	}
+
+	mapper := st.statusMapper
+	if mapper == nil {
+		mapper = DefaultStatusMapper
+	}
+	return r, mapper(err), err
+}
+
+// requestEvent verifies the payload with the secret picked by the SecretFunc
+// if any, verification failures are returned as a StripeInvalidEventError.
+func (st *Client) requestEvent(r *http.Request, raw []byte) (*RawEvent, error) {
+	signature := st.signature(r)
+	if st.secretFunc == nil || st.verifier != nil {
+		event, err := st.EventWithRaw(raw, signature)
+		if err != nil {
+			return nil, NewInvalidEventError(err)
+		}
+		return event, nil
+	}
+
+	secret, err := st.secretFunc(r)
+	if err != nil {
+		return nil, newError("Client.ServeHTTP", []interface{}{raw, signature}, err)
+	}
+	event, err := st.verify(st.defaultVerifier([]string{secret}), raw, signature)
+	if err != nil {
+		return nil, NewInvalidEventError(err)
+	}
+	return &RawEvent{Event: event, Raw: raw, Signature: signature}, nil
+}
+
+// WithMaxBodyBytes limits the size of the bodies ServeHTTP reads, larger ones
+// are rejected with 413 before any verification. Defaults to MAX_BODY_BYTES.
+func WithMaxBodyBytes(n int64) func(*Client) {
+	return func(c *Client) {
+		if n > 0 {
+			c.maxBodyBytes = n
+		}
+	}
+}
+
+// WithSignatureHeader sets the header ServeHTTP reads the signature from,
+// SIGNATURE_HEADER by default. The name is matched case-insensitively.
+func WithSignatureHeader(name string) func(*Client) {
+	return func(c *Client) {
+		if name != "" {
+			c.signatureHeader = name
+		}
+	}
+}
+
+func (st *Client) signature(r *http.Request) string {
+	if v := r.Header.Get(st.signatureHeader); v != "" {
+		return v
+	}
+	// Headers set on the map directly are not canonicalized.
+	for name, values := range r.Header {
+		if strings.EqualFold(name, st.signatureHeader) && len(values) > 0 {
+			return values[0]
+		}
+	}
+	return ""
+}
+
+// HandleRaw verifies the payload and dispatches it with Handle. Verification
+// failures are returned as a StripeInvalidEventError, any other error comes
+// from the handlers.
+func (st *Client) HandleRaw(raw []byte, signature string) error {
+	event, err := st.EventWithRaw(raw, signature)
+	if err != nil {
+		return NewInvalidEventError(err)
+	}
+	return st.HandleRawEvent(event)
+}
+
+func (st *Client) HandleRawParallel(raw []byte, signature string) error {
+	event, err := st.EventWithRaw(raw, signature)
+	if err != nil {
+		return NewInvalidEventError(err)
+	}
+	return st.HandleRawEventParallel(event)
+}
@@ -0,0 +1,379 @@
+package stripetotrello
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/skipper-digital-studio/stripetotrello/testutil"
+	stripe "github.com/stripe/stripe-go/v76"
+	"github.com/stripe/stripe-go/v76/webhook"
+)
+
+const testSecret = "whsec_test"
+
+func signature(secret string, payload []byte, at time.Time) string {
+	return webhook.GenerateTestSignedPayload(&webhook.UnsignedPayload{
+		Payload:   payload,
+		Secret:    secret,
+		Timestamp: at,
+	}).Header
+}
+
+func signedRequest(secret string, payload []byte) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/webhook", bytes.NewReader(payload))
+	req.Header.Set(SIGNATURE_HEADER, signature(secret, payload, time.Now()))
+	return req
+}
+
+func TestServeHTTP(t *testing.T) {
+	type testCase struct {
+		name   string
+		req    *http.Request
+		status int
+	}
+
+	client := NewClient(WithStripeWebhookSecret(testSecret))
+	client.AppendHandler("customer.created", func(_ *stripe.Event) (EventResponse, error) {
+		return "testing", nil
+	})
+	client.AppendHandler("customer.deleted", func(_ *stripe.Event) (EventResponse, error) {
+		return nil, newError("It fails", []interface{}{"error"}, fmt.Errorf("test"))
+	})
+
+	tcs := []testCase{
+		{"success", signedRequest(testSecret, testutil.Payload("customer.created")), http.StatusOK},
+		{"invalid signature", signedRequest("whsec_other", testutil.Payload("customer.created")), http.StatusBadRequest},
+		{"handler failure", signedRequest(testSecret, testutil.Payload("customer.deleted")), http.StatusInternalServerError},
+	}
+
+	for _, tc := range tcs {
+		rec := httptest.NewRecorder()
+		client.ServeHTTP(rec, tc.req)
+
+		if rec.Code != tc.status {
+			t.Errorf("Expected status %d for %s, got %d", tc.status, tc.name, rec.Code)
+		}
+	}
+}
+
+type requestKey struct{}
+
+func TestServeHTTPContext(t *testing.T) {
+	client := NewClient(WithStripeWebhookSecret(testSecret))
+
+	var value interface{}
+	var deadline bool
+	client.AppendHandlerCtx("customer.created", func(ctx context.Context, _ *stripe.Event) (EventResponse, error) {
+		value = ctx.Value(requestKey{})
+		_, deadline = ctx.Deadline()
+		return nil, nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.WithValue(context.Background(), requestKey{}, "request"), time.Minute)
+	defer cancel()
+	rec := httptest.NewRecorder()
+	client.ServeHTTP(rec, signedRequest(testSecret, testutil.Payload("customer.created")).WithContext(ctx))
+
+	if value != "request" {
+		t.Errorf("Expected the handlers to get the request context values, got %v", value)
+	}
+	if !deadline {
+		t.Errorf("Expected the handlers to get the request deadline")
+	}
+}
+
+func TestHandleRaw(t *testing.T) {
+	type testCase struct {
+		name    string
+		secret  string
+		event   string
+		invalid bool
+		fail    bool
+	}
+
+	fired := map[string]int{}
+	client := NewClient(WithStripeWebhookSecret(testSecret))
+	client.AppendHandler("customer.created", func(e *stripe.Event) (EventResponse, error) {
+		fired[string(e.Type)]++
+		return "testing", nil
+	})
+	client.AppendHandler("customer.deleted", func(_ *stripe.Event) (EventResponse, error) {
+		return nil, newError("It fails", []interface{}{"error"}, fmt.Errorf("test"))
+	})
+
+	tcs := []testCase{
+		{"signed", testSecret, "customer.created", false, false},
+		{"invalid signature", "whsec_other", "customer.created", true, true},
+		{"handler failure", testSecret, "customer.deleted", false, true},
+	}
+
+	for _, tc := range tcs {
+		for _, handle := range []func([]byte, string) error{client.HandleRaw, client.HandleRawParallel} {
+			req := signedRequest(tc.secret, testutil.Payload(tc.event))
+			err := handle(testutil.Payload(tc.event), req.Header.Get(SIGNATURE_HEADER))
+
+			if err != nil && !tc.fail {
+				t.Errorf("Expected %s to NOT fail, got %s", tc.name, err)
+			}
+			if err == nil && tc.fail {
+				t.Errorf("Expected %s to fail", tc.name)
+			}
+			if _, ok := err.(StripeInvalidEventError); ok != tc.invalid {
+				t.Errorf("Expected %s to return an invalid event error = %t, got %v", tc.name, tc.invalid, err)
+			}
+		}
+	}
+
+	if fired["customer.created"] != 2 {
+		t.Errorf("Expected handler to fire twice, fired %d times", fired["customer.created"])
+	}
+}
+
+func TestEventErrorClassification(t *testing.T) {
+	type testCase struct {
+		name      string
+		secret    string
+		payload   []byte
+		signature bool
+		parse     bool
+	}
+
+	client := NewClient(WithStripeWebhookSecret(testSecret))
+
+	tcs := []testCase{
+		{"valid", testSecret, testutil.Payload("customer.created"), false, false},
+		{"wrong secret", "whsec_other", testutil.Payload("customer.created"), true, false},
+		{"unsigned", "", testutil.Payload("customer.created"), true, false},
+		{"malformed json", testSecret, []byte(`{"id": "evt_test",`), false, true},
+	}
+
+	for _, tc := range tcs {
+		signature := ""
+		if tc.secret != "" {
+			signature = signedRequest(tc.secret, tc.payload).Header.Get(SIGNATURE_HEADER)
+		}
+
+		_, err := client.Event(tc.payload, signature)
+		if IsSignatureError(err) != tc.signature {
+			t.Errorf("Expected %s signature error = %t, got %v", tc.name, tc.signature, err)
+		}
+		if errors.Is(err, ErrPayloadParse) != tc.parse {
+			t.Errorf("Expected %s parse error = %t, got %v", tc.name, tc.parse, err)
+		}
+	}
+}
+
+func TestEventTolerance(t *testing.T) {
+	type testCase struct {
+		name       string
+		tolerance  time.Duration
+		age        time.Duration
+		shouldFail bool
+	}
+
+	tcs := []testCase{
+		{"fresh with default tolerance", 0, 0, false},
+		{"old with default tolerance", 0, time.Hour, true},
+		{"old with widened tolerance", 2 * time.Hour, time.Hour, false},
+		{"older than widened tolerance", 2 * time.Hour, 3 * time.Hour, true},
+	}
+
+	payload := testutil.Payload("customer.created")
+	for _, tc := range tcs {
+		client := NewClient(WithStripeWebhookSecret(testSecret), WithTolerance(tc.tolerance))
+
+		_, err := client.Event(payload, signature(testSecret, payload, time.Now().Add(-tc.age)))
+		if err != nil && !tc.shouldFail {
+			t.Errorf("Expected %s to NOT fail, got %s", tc.name, err)
+		}
+		if err == nil && tc.shouldFail {
+			t.Errorf("Expected %s to fail", tc.name)
+		}
+		if err != nil && !errors.Is(err, webhook.ErrTooOld) {
+			t.Errorf("Expected %s to fail with webhook.ErrTooOld, got %s", tc.name, err)
+		}
+	}
+}
+
+func TestEventAPIVersionMismatch(t *testing.T) {
+	type testCase struct {
+		name       string
+		cfgs       []func(*Client)
+		shouldFail bool
+	}
+
+	payload := []byte(`{"id": "evt_test", "object": "event", "type": "customer.created", "api_version": "2020-08-27", "data": {"object": {}}}`)
+
+	tcs := []testCase{
+		{"strict version", []func(*Client){WithStripeWebhookSecret(testSecret)}, true},
+		{"ignored version", []func(*Client){WithStripeWebhookSecret(testSecret), WithIgnoreAPIVersionMismatch()}, false},
+	}
+
+	for _, tc := range tcs {
+		client := NewClient(tc.cfgs...)
+
+		event, err := client.Event(payload, signature(testSecret, payload, time.Now()))
+		if err != nil && !tc.shouldFail {
+			t.Errorf("Expected %s to NOT fail, got %s", tc.name, err)
+		}
+		if err == nil && tc.shouldFail {
+			t.Errorf("Expected %s to fail", tc.name)
+		}
+		if err == nil && event.APIVersion != "2020-08-27" {
+			t.Errorf("Expected %s to keep the event API version, got %s", tc.name, event.APIVersion)
+		}
+	}
+}
+
+func TestEventSecretRotation(t *testing.T) {
+	type testCase struct {
+		name       string
+		secret     string
+		shouldFail bool
+	}
+
+	client := NewClient(WithStripeWebhookSecrets("whsec_old", "whsec_new"))
+
+	tcs := []testCase{
+		{"first secret", "whsec_old", false},
+		{"second secret", "whsec_new", false},
+		{"unknown secret", "whsec_other", true},
+	}
+
+	payload := testutil.Payload("customer.created")
+	for _, tc := range tcs {
+		_, err := client.Event(payload, signature(tc.secret, payload, time.Now()))
+		if err != nil && !tc.shouldFail {
+			t.Errorf("Expected %s to NOT fail, got %s", tc.name, err)
+		}
+		if err == nil && tc.shouldFail {
+			t.Errorf("Expected %s to fail", tc.name)
+		}
+		if err != nil && !errors.Is(err, webhook.ErrNoValidSignature) {
+			t.Errorf("Expected %s to fail with webhook.ErrNoValidSignature, got %s", tc.name, err)
+		}
+	}
+}
+
+func TestInsecureSkipVerify(t *testing.T) {
+	type testCase struct {
+		name       string
+		cfgs       []func(*Client)
+		shouldFail bool
+		warning    string
+	}
+
+	tcs := []testCase{
+		{"skip verify", []func(*Client){WithInsecureSkipVerify()}, false, "webhook signature verification is DISABLED, never use WithInsecureSkipVerify in production"},
+		{"secret takes precedence", []func(*Client){WithInsecureSkipVerify(), WithStripeWebhookSecret(testSecret)}, true, "insecure skip verify is ignored, a webhook secret is configured"},
+		{"verify by default", nil, true, ""},
+	}
+
+	for _, tc := range tcs {
+		logger := &capturingLogger{}
+		client := NewClient(append(tc.cfgs, WithLogger(logger))...)
+		fired := false
+		client.AppendHandler("customer.created", func(_ *stripe.Event) (EventResponse, error) {
+			fired = true
+			return "ok", nil
+		})
+
+		err := client.HandleRaw(testutil.Payload("customer.created"), "")
+		if err != nil && !tc.shouldFail {
+			t.Errorf("Expected %s to NOT fail, got %s", tc.name, err)
+		}
+		if err == nil && tc.shouldFail {
+			t.Errorf("Expected %s to fail", tc.name)
+		}
+		if fired == tc.shouldFail {
+			t.Errorf("Expected %s to dispatch = %t", tc.name, !tc.shouldFail)
+		}
+
+		warned := false
+		for _, line := range logger.lines {
+			warned = warned || (line.level == "error" && line.msg == tc.warning)
+		}
+		if warned != (tc.warning != "") {
+			t.Errorf("Expected %s to log %q", tc.name, tc.warning)
+		}
+	}
+}
+
+func TestSignatureHeader(t *testing.T) {
+	type testCase struct {
+		name   string
+		cfgs   []func(*Client)
+		header string
+		status int
+	}
+
+	tcs := []testCase{
+		{"default", nil, "Stripe-Signature", http.StatusOK},
+		{"custom", []func(*Client){WithSignatureHeader("X-Forwarded-Signature")}, "X-Forwarded-Signature", http.StatusOK},
+		{"custom lower case", []func(*Client){WithSignatureHeader("X-Forwarded-Signature")}, "x-forwarded-signature", http.StatusOK},
+		{"custom ignores default", []func(*Client){WithSignatureHeader("X-Forwarded-Signature")}, "Stripe-Signature", http.StatusBadRequest},
+	}
+
+	payload := testutil.Payload("customer.created")
+	for _, tc := range tcs {
+		client := NewClient(append(tc.cfgs, WithStripeWebhookSecret(testSecret))...)
+		client.AppendHandler("customer.created", func(_ *stripe.Event) (EventResponse, error) {
+			return "ok", nil
+		})
+
+		req := httptest.NewRequest(http.MethodPost, "/webhook", bytes.NewReader(payload))
+		req.Header[tc.header] = []string{signature(testSecret, payload, time.Now())}
+		rec := httptest.NewRecorder()
+		client.ServeHTTP(rec, req)
+
+		if rec.Code != tc.status {
+			t.Errorf("Expected status %d for %s, got %d", tc.status, tc.name, rec.Code)
+		}
+	}
+}
+
+func TestMaxBodyBytes(t *testing.T) {
+	type testCase struct {
+		name   string
+		cfgs   []func(*Client)
+		size   int
+		status int
+	}
+
+	tcs := []testCase{
+		{"default limit", nil, int(MAX_BODY_BYTES) + 1, http.StatusRequestEntityTooLarge},
+		{"custom limit", []func(*Client){WithMaxBodyBytes(1024)}, 1025, http.StatusRequestEntityTooLarge},
+		{"within limit", []func(*Client){WithMaxBodyBytes(1024)}, 1024, http.StatusBadRequest},
+	}
+
+	for _, tc := range tcs {
+		verified := false
+		client := NewClient(append(tc.cfgs, WithVerifier(verifierFunc(func(_ []byte, _ string) (*stripe.Event, error) {
+			verified = true
+			return nil, ErrSignatureVerification
+		})))...)
+
+		req := httptest.NewRequest(http.MethodPost, "/webhook", bytes.NewReader(make([]byte, tc.size)))
+		rec := httptest.NewRecorder()
+		client.ServeHTTP(rec, req)
+
+		if rec.Code != tc.status {
+			t.Errorf("Expected status %d for %s, got %d", tc.status, tc.name, rec.Code)
+		}
+		if verified == (tc.status == http.StatusRequestEntityTooLarge) {
+			t.Errorf("Expected %s to reach verification = %t", tc.name, !verified)
+		}
+	}
+}
+
+type verifierFunc func(raw []byte, signature string) (*stripe.Event, error)
+
+func (f verifierFunc) Verify(raw []byte, signature string) (*stripe.Event, error) {
+	return f(raw, signature)
+}
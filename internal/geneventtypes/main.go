@@ -0,0 +1,81 @@
+// Command geneventtypes writes eventtypes_generated.go from the EventType
+// constants of the stripe-go module in go.mod.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	STRIPE_MODULE = "github.com/stripe/stripe-go/v76"
+	OUTPUT        = "eventtypes_generated.go"
+)
+
+func main() {
+	out, err := exec.Command("go", "list", "-m", "-f", "{{.Dir}}", STRIPE_MODULE).Output()
+	if err != nil {
+		log.Fatalf("locating %s: %s", STRIPE_MODULE, err)
+	}
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filepath.Join(strings.TrimSpace(string(out)), "event.go"), nil, 0)
+	if err != nil {
+		log.Fatalf("parsing event.go: %s", err)
+	}
+
+	type constant struct {
+		name  string
+		value string
+	}
+	var constants []constant
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.CONST {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			vs := spec.(*ast.ValueSpec)
+			if ident, ok := vs.Type.(*ast.Ident); !ok || ident.Name != "EventType" {
+				continue
+			}
+			for i, name := range vs.Names {
+				value, err := strconv.Unquote(vs.Values[i].(*ast.BasicLit).Value)
+				if err != nil {
+					log.Fatalf("reading %s: %s", name.Name, err)
+				}
+				constants = append(constants, constant{name.Name, value})
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by internal/geneventtypes from %s; DO NOT EDIT.\n\n", STRIPE_MODULE)
+	fmt.Fprintf(&buf, "package stripetotrello\n\nconst (\n")
+	for _, c := range constants {
+		fmt.Fprintf(&buf, "\t%s = %q\n", c.name, c.value)
+	}
+	fmt.Fprintf(&buf, ")\n\nvar knownEventTypes = map[EventType]struct{}{\n")
+	for _, c := range constants {
+		fmt.Fprintf(&buf, "\t%s: {},\n", c.name)
+	}
+	fmt.Fprintf(&buf, "}\n")
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatalf("formatting: %s", err)
+	}
+	if err := os.WriteFile(OUTPUT, src, 0o644); err != nil {
+		log.Fatalf("writing %s: %s", OUTPUT, err)
+	}
+}
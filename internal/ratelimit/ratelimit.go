@@ -0,0 +1,78 @@
+// Package ratelimit is the token bucket behind the handler rate limits of
+// stripetotrello and the request rate limit of its trello package.
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Bucket starts full, it is refilled at RPS tokens per second of Now time and
+// holds up to Burst tokens. Now defaults to time.Now and Sleep, which waits
+// for the next token, to a timer.
+type Bucket struct {
+	RPS   float64
+	Burst float64
+	Now   func() time.Time
+	Sleep func(ctx context.Context, d time.Duration) error
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+func New(rps, burst float64) *Bucket {
+	return &Bucket{RPS: rps, Burst: burst, tokens: burst}
+}
+
+// Wait takes a token, sleeping until there is one or ctx is done.
+func (b *Bucket) Wait(ctx context.Context) error {
+	now, sleep := b.Now, b.Sleep
+	if now == nil {
+		now = time.Now
+	}
+	if sleep == nil {
+		sleep = Sleep
+	}
+
+	for {
+		b.mu.Lock()
+		t := now()
+		if b.last.IsZero() {
+			b.last = t
+		}
+		b.tokens += t.Sub(b.last).Seconds() * b.RPS
+		if b.tokens > b.Burst {
+			b.tokens = b.Burst
+		}
+		b.last = t
+
+		if b.tokens >= 1 {
+			b.tokens--
+			b.mu.Unlock()
+			return nil
+		}
+		delay := time.Duration((1 - b.tokens) / b.RPS * float64(time.Second))
+		b.mu.Unlock()
+
+		if err := sleep(ctx, delay); err != nil {
+			return err
+		}
+	}
+}
+
+// Sleep waits for d or until ctx is done, returning ctx.Err() in that case.
+func Sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,58 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBucketWait(t *testing.T) {
+	type testCase struct {
+		name  string
+		rps   float64
+		burst float64
+		waits int
+		slept time.Duration
+	}
+
+	tcs := []testCase{
+		{"within the burst", 10, 5, 5, 0},
+		{"past the burst", 10, 5, 8, 300 * time.Millisecond},
+		{"burst of one", 4, 1, 3, 500 * time.Millisecond},
+	}
+
+	for _, tc := range tcs {
+		now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+		var slept time.Duration
+		b := New(tc.rps, tc.burst)
+		b.Now = func() time.Time { return now }
+		b.Sleep = func(_ context.Context, d time.Duration) error {
+			now = now.Add(d)
+			slept += d
+			return nil
+		}
+
+		for i := 0; i < tc.waits; i++ {
+			if err := b.Wait(context.Background()); err != nil {
+				t.Fatalf("Expected %s to NOT fail, got %s", tc.name, err)
+			}
+		}
+		if slept < tc.slept-time.Millisecond || slept > tc.slept+time.Millisecond {
+			t.Errorf("Expected %s to sleep %s, slept %s", tc.name, tc.slept, slept)
+		}
+	}
+}
+
+func TestBucketWaitCanceled(t *testing.T) {
+	b := New(1, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := b.Wait(ctx); err != nil {
+		t.Errorf("Expected the burst token to be taken, got %s", err)
+	}
+	if err := b.Wait(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected waiting for a token to respect the context, got %v", err)
+	}
+}
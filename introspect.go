@@ -0,0 +1,99 @@
+package stripetotrello
+
+import (
+	"sort"
+	"time"
+)
+
+// RegisteredEventTypes returns the registered event types and patterns, sorted.
+func (st *Client) RegisteredEventTypes() []string {
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+
+	output := make([]string, 0, len(st.handlers))
+	for eventType, handlers := range st.handlers {
+		if len(handlers) > 0 {
+			output = append(output, eventType)
+		}
+	}
+	sort.Strings(output)
+	return output
+}
+
+// HandlerCount returns how many handlers an event of the given type is
+// dispatched to, following the same pattern precedence as Handle.
+func (st *Client) HandlerCount(eventType string) int {
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+
+	handlers, _ := match(st.handlers, eventType)
+	return len(handlers)
+}
+
+func (st *Client) HasHandler(eventType string) bool {
+	return st.HandlerCount(eventType) > 0
+}
+
+type ClientSnapshot struct {
+	// EventTypes are the registered event types and patterns, sorted, with
+	// the number of handlers registered for each in HandlerCounts.
+	EventTypes    []string
+	HandlerCounts map[string]int
+
+	SuccessHandlers       []string
+	FailureHandlers       []string
+	DefaultSuccessHandler bool
+	DefaultFailureHandler bool
+
+	WebhookSecrets   int
+	Tolerance        time.Duration
+	HandlerTimeout   time.Duration
+	MaxConcurrency   int
+	RetryAttempts    int
+	ContinueOnError  bool
+	RecoverPanics    bool
+	LivemodeOnly     bool
+	TestmodeOnly     bool
+	StrictEventTypes bool
+}
+
+// Snapshot returns a copy of the registrations and options, changing it does
+// not affect the client. Secrets are only counted.
+func (st *Client) Snapshot() ClientSnapshot {
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+
+	snap := ClientSnapshot{
+		EventTypes:            make([]string, 0, len(st.handlers)),
+		HandlerCounts:         make(map[string]int, len(st.handlers)),
+		SuccessHandlers:       sortedKeys(st.successHandler),
+		FailureHandlers:       sortedKeys(st.failureHandler),
+		DefaultSuccessHandler: st.defaultSuccess != nil,
+		DefaultFailureHandler: st.defaultFailure != nil,
+		WebhookSecrets:        len(st.stripeWebhookSecrets),
+		Tolerance:             st.tolerance,
+		HandlerTimeout:        st.handlerTimeout,
+		MaxConcurrency:        st.maxConcurrency,
+		RetryAttempts:         st.retryAttempts,
+		ContinueOnError:       st.continueOnError,
+		RecoverPanics:         st.recoverPanics,
+		LivemodeOnly:          st.livemodeOnly,
+		TestmodeOnly:          st.testmodeOnly,
+		StrictEventTypes:      st.strictEventTypes,
+	}
+	for eventType, handlers := range st.handlers {
+		snap.EventTypes = append(snap.EventTypes, eventType)
+		snap.HandlerCounts[eventType] = len(handlers)
+	}
+	sort.Strings(snap.EventTypes)
+	return snap
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
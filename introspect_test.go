@@ -0,0 +1,88 @@
+package stripetotrello
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	stripe "github.com/stripe/stripe-go/v76"
+)
+
+func TestIntrospection(t *testing.T) {
+	type testCase struct {
+		event  string
+		count  int
+		exists bool
+	}
+
+	noop := func(_ *stripe.Event) (EventResponse, error) {
+		return nil, nil
+	}
+
+	client := NewClient()
+	client.AppendHandler("customer.updated", noop)
+	client.AppendHandler("customer.created", noop, noop, noop)
+	client.AppendHandler("invoice.*", noop, noop)
+
+	expected := []string{"customer.created", "customer.updated", "invoice.*"}
+	types := client.RegisteredEventTypes()
+	if len(types) != len(expected) {
+		t.Fatalf("Expected event types %v, got %v", expected, types)
+	}
+	for i := range expected {
+		if types[i] != expected[i] {
+			t.Errorf("Expected event type %s at index %d, got %s", expected[i], i, types[i])
+		}
+	}
+
+	tcs := []testCase{
+		{"customer.created", 3, true},
+		{"customer.updated", 1, true},
+		{"invoice.paid", 2, true},
+		{"customer.deleted", 0, false},
+	}
+
+	for _, tc := range tcs {
+		if count := client.HandlerCount(tc.event); count != tc.count {
+			t.Errorf("Expected %d handlers for event type = %s, got %d", tc.count, tc.event, count)
+		}
+		if exists := client.HasHandler(tc.event); exists != tc.exists {
+			t.Errorf("Expected HasHandler = %t for event type = %s, got %t", tc.exists, tc.event, exists)
+		}
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	noop := func(_ *stripe.Event) (EventResponse, error) {
+		return nil, nil
+	}
+
+	client := NewClient(WithStripeWebhookSecrets("whsec_old", "whsec_new"), WithTolerance(time.Minute), WithMaxConcurrency(4), WithContinueOnError())
+	client.AppendHandler("customer.created", noop, noop)
+	client.AppendHandler("invoice.*", noop)
+	client.AddSuccessHandler("customer.created", func(_ *stripe.Event, _ []EventResponse) error { return nil })
+	client.AddFailureHandler("invoice.*", func(_ *stripe.Event, err error) error { return err })
+	client.SetDefaultFailureHandler(func(_ *stripe.Event, err error) error { return err })
+
+	snap := client.Snapshot()
+	if strings.Join(snap.EventTypes, ",") != "customer.created,invoice.*" {
+		t.Errorf("Expected the registered event types, got %v", snap.EventTypes)
+	}
+	if snap.HandlerCounts["customer.created"] != 2 || snap.HandlerCounts["invoice.*"] != 1 {
+		t.Errorf("Expected the handler counts, got %v", snap.HandlerCounts)
+	}
+	if strings.Join(snap.SuccessHandlers, ",") != "customer.created" || strings.Join(snap.FailureHandlers, ",") != "invoice.*" {
+		t.Errorf("Expected the success and failure handler types, got %v and %v", snap.SuccessHandlers, snap.FailureHandlers)
+	}
+	if snap.DefaultSuccessHandler || !snap.DefaultFailureHandler {
+		t.Errorf("Expected only a default failure handler, got %+v", snap)
+	}
+	if snap.WebhookSecrets != 2 || snap.Tolerance != time.Minute || snap.MaxConcurrency != 4 || !snap.ContinueOnError {
+		t.Errorf("Expected the configured options, got %+v", snap)
+	}
+
+	snap.HandlerCounts["customer.created"] = 10
+	if client.HandlerCount("customer.created") != 2 {
+		t.Errorf("Expected the snapshot to be a copy")
+	}
+}
@@ -0,0 +1,11 @@
+module github.com/skipper-digital-studio/stripetotrello/lambdaadapter
+
+go 1.22.3
+
+require (
+	github.com/aws/aws-lambda-go v1.47.0
+	github.com/skipper-digital-studio/stripetotrello v0.0.0-00010101000000-000000000000
+	github.com/stripe/stripe-go/v76 v76.25.0
+)
+
+replace github.com/skipper-digital-studio/stripetotrello => ../
@@ -0,0 +1,59 @@
+package lambdaadapter
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"net/http"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/skipper-digital-studio/stripetotrello"
+)
+
+// Handler verifies and dispatches API Gateway proxy requests with
+// Client.HandleRequest, answering with the status the client maps the
+// outcome to. Handler errors are only reflected in the status code, the
+// returned error is always nil so API Gateway does not turn them into a 502.
+func Handler(c *stripetotrello.Client) func(context.Context, events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	return func(ctx context.Context, req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+		raw := []byte(req.Body)
+		if req.IsBase64Encoded {
+			decoded, err := base64.StdEncoding.DecodeString(req.Body)
+			if err != nil {
+				return response(http.StatusBadRequest), nil
+			}
+			raw = decoded
+		}
+
+		r, err := request(ctx, req, raw)
+		if err != nil {
+			return response(http.StatusBadRequest), nil
+		}
+		status, _ := c.HandleRequest(r)
+		return response(status), nil
+	}
+}
+
+// request rebuilds the HTTP request API Gateway received, the client looks
+// the signature header up case-insensitively.
+func request(ctx context.Context, req events.APIGatewayProxyRequest, raw []byte) (*http.Request, error) {
+	r, err := http.NewRequestWithContext(ctx, req.HTTPMethod, req.Path, bytes.NewReader(raw))
+	if err != nil {
+		return nil, err
+	}
+	for name, values := range req.MultiValueHeaders {
+		for _, v := range values {
+			r.Header.Add(name, v)
+		}
+	}
+	for name, v := range req.Headers {
+		if r.Header.Get(name) == "" {
+			r.Header.Set(name, v)
+		}
+	}
+	return r, nil
+}
+
+func response(status int) events.APIGatewayProxyResponse {
+	return events.APIGatewayProxyResponse{StatusCode: status, Body: http.StatusText(status)}
+}
@@ -0,0 +1,68 @@
+package lambdaadapter
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/skipper-digital-studio/stripetotrello"
+	"github.com/skipper-digital-studio/stripetotrello/testutil"
+	stripe "github.com/stripe/stripe-go/v76"
+)
+
+const testSecret = "whsec_test"
+
+func TestHandler(t *testing.T) {
+	type testCase struct {
+		name   string
+		secret string
+		event  string
+		header string
+		base64 bool
+		status int
+	}
+
+	client := stripetotrello.NewClient(stripetotrello.WithStripeWebhookSecret(testSecret), stripetotrello.WithMaxBodyBytes(256))
+	client.AppendHandler("customer.created", func(_ *stripe.Event) (stripetotrello.EventResponse, error) {
+		return "ok", nil
+	})
+	client.AppendHandler("customer.deleted", func(_ *stripe.Event) (stripetotrello.EventResponse, error) {
+		return nil, fmt.Errorf("test")
+	})
+
+	tcs := []testCase{
+		{"success", testSecret, "customer.created", "Stripe-Signature", false, http.StatusOK},
+		{"lower case header", testSecret, "customer.created", "stripe-signature", false, http.StatusOK},
+		{"base64 body", testSecret, "customer.created", "Stripe-Signature", true, http.StatusOK},
+		{"invalid signature", "whsec_other", "customer.created", "Stripe-Signature", false, http.StatusBadRequest},
+		{"handler failure", testSecret, "customer.deleted", "Stripe-Signature", false, http.StatusInternalServerError},
+		{"unhandled event type", testSecret, "invoice.paid", "Stripe-Signature", false, http.StatusOK},
+		{"too large", testSecret, "customer.created" + strings.Repeat(" ", 256), "Stripe-Signature", false, http.StatusRequestEntityTooLarge},
+	}
+
+	handler := Handler(client)
+	for _, tc := range tcs {
+		payload := testutil.Payload(tc.event)
+		req := events.APIGatewayProxyRequest{
+			Headers: map[string]string{tc.header: testutil.SignPayload(tc.secret, payload, time.Now())},
+			Body:    string(payload),
+		}
+		if tc.base64 {
+			req.Body = base64.StdEncoding.EncodeToString(payload)
+			req.IsBase64Encoded = true
+		}
+
+		res, err := handler(context.Background(), req)
+		if err != nil {
+			t.Errorf("Expected %s to NOT return an error, got %s", tc.name, err)
+		}
+		if res.StatusCode != tc.status {
+			t.Errorf("Expected status %d for %s, got %d", tc.status, tc.name, res.StatusCode)
+		}
+	}
+}
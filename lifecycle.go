@@ -0,0 +1,63 @@
+package stripetotrello
+
+import (
+	"context"
+
+	stripe "github.com/stripe/stripe-go/v76"
+)
+
+// begin registers a dispatch as in flight, the caller must call
+// st.inflight.Done once it returns.
+func (st *Client) begin() error {
+	st.lifecycle.Lock()
+	defer st.lifecycle.Unlock()
+
+	if st.closed {
+		return ErrClientClosed
+	}
+	st.inflight.Add(1)
+	return nil
+}
+
+// Close stops accepting new dispatches, which fail with ErrClientClosed, and
+// waits for the in-flight ones to finish or for ctx to expire. The worker pool
+// stops once they are done, even when ctx expired first.
+func (st *Client) Close(ctx context.Context) error {
+	st.lifecycle.Lock()
+	st.closed = true
+	st.lifecycle.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		st.inflight.Wait()
+		st.pool.stop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// HandleAsync dispatches the event with Handle on a background goroutine and
+// calls done, when not nil, with the outcome. Close waits for it to finish.
+func (st *Client) HandleAsync(event *stripe.Event, done func(error)) {
+	if err := st.begin(); err != nil {
+		if done != nil {
+			done(newError("Client.HandleAsync", []interface{}{event}, err))
+		}
+		return
+	}
+
+	go func() {
+		defer st.inflight.Done()
+
+		_, err := st.dispatch(context.Background(), event)
+		if done != nil {
+			done(err)
+		}
+	}()
+}
@@ -0,0 +1,97 @@
+package stripetotrello
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	stripe "github.com/stripe/stripe-go/v76"
+)
+
+func TestClose(t *testing.T) {
+	type testCase struct {
+		name       string
+		timeout    time.Duration
+		finished   bool
+		shouldFail bool
+	}
+
+	tcs := []testCase{
+		{"waits for completion", time.Second, true, false},
+		{"context timeout", 10 * time.Millisecond, false, true},
+	}
+
+	for _, tc := range tcs {
+		var finished int32
+		started := make(chan struct{})
+
+		client := NewClient()
+		client.AppendHandler("customer.created", func(_ *stripe.Event) (EventResponse, error) {
+			close(started)
+			time.Sleep(100 * time.Millisecond)
+			atomic.StoreInt32(&finished, 1)
+			return nil, nil
+		})
+
+		go client.HandleParallel(&stripe.Event{Type: "customer.created"})
+		<-started
+
+		ctx, cancel := context.WithTimeout(context.Background(), tc.timeout)
+		err := client.Close(ctx)
+		cancel()
+
+		if err != nil && !tc.shouldFail {
+			t.Errorf("Expected %s to NOT fail, got %s", tc.name, err)
+		}
+		if err == nil && tc.shouldFail {
+			t.Errorf("Expected %s to fail", tc.name)
+		}
+		if (atomic.LoadInt32(&finished) == 1) != tc.finished {
+			t.Errorf("Expected %s to return with the handler finished = %t", tc.name, tc.finished)
+		}
+
+		if err := client.HandleParallel(&stripe.Event{Type: "customer.created"}); !errors.Is(err, ErrClientClosed) {
+			t.Errorf("Expected %s to reject new dispatches with ErrClientClosed, got %v", tc.name, err)
+		}
+		if err := client.Handle(&stripe.Event{Type: "customer.created"}); !errors.Is(err, ErrClientClosed) {
+			t.Errorf("Expected %s to reject new dispatches with ErrClientClosed, got %v", tc.name, err)
+		}
+	}
+}
+
+func TestHandleAsync(t *testing.T) {
+	cause := errors.New("trello unavailable")
+
+	client := NewClient()
+	client.AppendHandler("customer.created", func(_ *stripe.Event) (EventResponse, error) {
+		time.Sleep(50 * time.Millisecond)
+		return nil, cause
+	})
+
+	results := make(chan error, 1)
+	client.HandleAsync(&stripe.Event{Type: "customer.created"}, func(err error) {
+		results <- err
+	})
+
+	if err := client.Close(context.Background()); err != nil {
+		t.Fatalf("Expected Close to wait for the async dispatch, got %s", err)
+	}
+
+	select {
+	case err := <-results:
+		if !errors.Is(err, cause) {
+			t.Errorf("Expected done to receive the handler error, got %v", err)
+		}
+	default:
+		t.Errorf("Expected done to be called before Close returned")
+	}
+
+	client.HandleAsync(&stripe.Event{Type: "customer.created"}, func(err error) {
+		results <- err
+	})
+	if err := <-results; !errors.Is(err, ErrClientClosed) {
+		t.Errorf("Expected a closed client to reject async dispatches, got %v", err)
+	}
+}
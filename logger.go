@@ -0,0 +1,26 @@
+package stripetotrello
+
+type (
+	// Logger receives the client logs, keysAndValues alternate between a key
+	// and its value, as in "event_id", event.ID.
+	Logger interface {
+		Debug(msg string, keysAndValues ...interface{})
+		Info(msg string, keysAndValues ...interface{})
+		Error(msg string, keysAndValues ...interface{})
+	}
+
+	noopLogger struct{}
+)
+
+func (noopLogger) Debug(string, ...interface{}) {}
+func (noopLogger) Info(string, ...interface{})  {}
+func (noopLogger) Error(string, ...interface{}) {}
+
+func WithLogger(l Logger) func(*Client) {
+	return func(c *Client) {
+		if l == nil {
+			l = noopLogger{}
+		}
+		c.logger = l
+	}
+}
@@ -0,0 +1,89 @@
+package stripetotrello
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	stripe "github.com/stripe/stripe-go/v76"
+)
+
+type (
+	logLine struct {
+		level         string
+		msg           string
+		keysAndValues []interface{}
+	}
+
+	capturingLogger struct {
+		mu    sync.Mutex
+		lines []logLine
+	}
+)
+
+func (c *capturingLogger) log(level, msg string, keysAndValues []interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lines = append(c.lines, logLine{level, msg, keysAndValues})
+}
+
+func (c *capturingLogger) Debug(msg string, keysAndValues ...interface{}) {
+	c.log("debug", msg, keysAndValues)
+}
+
+func (c *capturingLogger) Info(msg string, keysAndValues ...interface{}) {
+	c.log("info", msg, keysAndValues)
+}
+
+func (c *capturingLogger) Error(msg string, keysAndValues ...interface{}) {
+	c.log("error", msg, keysAndValues)
+}
+
+func (c *capturingLogger) value(line logLine, key string) interface{} {
+	for i := 0; i+1 < len(line.keysAndValues); i += 2 {
+		if line.keysAndValues[i] == key {
+			return line.keysAndValues[i+1]
+		}
+	}
+	return nil
+}
+
+func TestLogger(t *testing.T) {
+	type testCase struct {
+		level string
+		msg   string
+	}
+
+	logger := &capturingLogger{}
+	client := NewClient(WithLogger(logger))
+	client.AppendHandler("customer.created", func(_ *stripe.Event) (EventResponse, error) {
+		return "ok", nil
+	}, func(_ *stripe.Event) (EventResponse, error) {
+		return nil, fmt.Errorf("trello unavailable")
+	})
+
+	if err := client.Handle(&stripe.Event{ID: "evt_1", Type: "customer.created"}); err == nil {
+		t.Fatalf("Event should have failed event type = customer.created")
+	}
+
+	tcs := []testCase{
+		{"debug", "dispatching event"},
+		{"error", "handler failed"},
+	}
+
+	if len(logger.lines) != len(tcs) {
+		t.Fatalf("Expected %d log lines, got %+v", len(tcs), logger.lines)
+	}
+	for i, tc := range tcs {
+		line := logger.lines[i]
+		if line.level != tc.level || line.msg != tc.msg {
+			t.Errorf("Expected log line %s %q, got %s %q", tc.level, tc.msg, line.level, line.msg)
+		}
+		if id := logger.value(line, "event_id"); id != "evt_1" {
+			t.Errorf("Expected log line %q to carry event_id evt_1, got %v", line.msg, id)
+		}
+	}
+	if handler := logger.value(logger.lines[1], "handler"); handler != 1 {
+		t.Errorf("Expected the failing handler index 1, got %v", handler)
+	}
+}
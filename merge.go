@@ -0,0 +1,98 @@
+package stripetotrello
+
+import (
+	"fmt"
+)
+
+// Merge appends the handlers and the success handlers of other after st ones,
+// keeping priorities, and copies its failure handlers. A failure or detailed
+// handler set on both clients for the same event type, or a default handler
+// set on both, fails with ErrMergeConflict and leaves st unchanged. Webhook
+// secrets and the other options are not merged.
+func (st *Client) Merge(other *Client) error {
+	if other == st {
+		return newError("Client.Merge", []interface{}{other}, fmt.Errorf("cannot merge a client into itself"))
+	}
+
+	other.mu.RLock()
+	handlers := make(map[string][]registeredHandler, len(other.handlers))
+	for eventType, list := range other.handlers {
+		if len(list) == 0 {
+			continue
+		}
+		handlers[eventType] = append([]registeredHandler(nil), list...)
+	}
+	successHandler := copyMap(other.successHandler)
+	failureHandler := copyMap(other.failureHandler)
+	successWithErrors := copyMap(other.successWithErrors)
+	successDetailed := copyMap(other.successDetailed)
+	failureDetailed := copyMap(other.failureDetailed)
+	defaultSuccess, defaultFailure := other.defaultSuccess, other.defaultFailure
+	other.mu.RUnlock()
+
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	for _, conflict := range []struct {
+		kind  string
+		types []string
+	}{
+		{"failure", conflicts(st.failureHandler, failureHandler)},
+		{"success with errors", conflicts(st.successWithErrors, successWithErrors)},
+		{"detailed success", conflicts(st.successDetailed, successDetailed)},
+		{"detailed failure", conflicts(st.failureDetailed, failureDetailed)},
+	} {
+		if len(conflict.types) > 0 {
+			return newError("Client.Merge", []interface{}{conflict.kind, conflict.types}, ErrMergeConflict)
+		}
+	}
+	if (st.defaultSuccess != nil && defaultSuccess != nil) || (st.defaultFailure != nil && defaultFailure != nil) {
+		return newError("Client.Merge", []interface{}{"default"}, ErrMergeConflict)
+	}
+
+	for eventType, list := range handlers {
+		for _, rh := range list {
+			st.handlers[eventType] = insertHandler(st.handlers[eventType], rh)
+		}
+	}
+	for eventType, list := range successHandler {
+		st.successHandler[eventType] = append(st.successHandler[eventType], list...)
+	}
+	for eventType, h := range failureHandler {
+		st.failureHandler[eventType] = h
+	}
+	for eventType, h := range successWithErrors {
+		st.successWithErrors[eventType] = h
+	}
+	for eventType, h := range successDetailed {
+		st.successDetailed[eventType] = h
+	}
+	for eventType, h := range failureDetailed {
+		st.failureDetailed[eventType] = h
+	}
+	if defaultSuccess != nil {
+		st.defaultSuccess = defaultSuccess
+	}
+	if defaultFailure != nil {
+		st.defaultFailure = defaultFailure
+	}
+	return nil
+}
+
+func copyMap[T any](m map[string]T) map[string]T {
+	out := make(map[string]T, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
+}
+
+func conflicts[T any](a, b map[string]T) []string {
+	var types []string
+	for eventType := range b {
+		if _, ok := a[eventType]; ok {
+			types = append(types, eventType)
+		}
+	}
+	return types
+}
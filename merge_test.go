@@ -0,0 +1,75 @@
+package stripetotrello
+
+import (
+	"errors"
+	"testing"
+
+	stripe "github.com/stripe/stripe-go/v76"
+)
+
+func TestMerge(t *testing.T) {
+	noop := func(_ *stripe.Event) (EventResponse, error) {
+		return nil, nil
+	}
+	success := func(_ *stripe.Event, _ []EventResponse) error {
+		return nil
+	}
+
+	billing := NewClient(WithStripeWebhookSecret("whsec_billing"))
+	billing.AppendHandler("invoice.paid", noop, noop)
+	billing.AppendHandler("customer.created", noop)
+	billing.AddSuccessHandler("invoice.paid", success)
+
+	subscriptions := NewClient(WithStripeWebhookSecret("whsec_subscriptions"))
+	subscriptions.AppendHandler("customer.subscription.created", noop)
+	subscriptions.AppendHandler("customer.created", noop, noop)
+	subscriptions.AddSuccessHandler("customer.subscription.created", success)
+
+	if err := billing.Merge(subscriptions); err != nil {
+		t.Fatalf("Merge should have NOT failed, got %s", err)
+	}
+
+	type testCase struct {
+		event string
+		count int
+	}
+	tcs := []testCase{
+		{"invoice.paid", 2},
+		{"customer.created", 3},
+		{"customer.subscription.created", 1},
+	}
+	for _, tc := range tcs {
+		if n := billing.HandlerCount(tc.event); n != tc.count {
+			t.Errorf("Expected %d handlers for %s, got %d", tc.count, tc.event, n)
+		}
+	}
+	if _, ok := billing.successFor("customer.subscription.created"); !ok {
+		t.Errorf("Expected the success handler to be merged")
+	}
+	if len(billing.stripeWebhookSecrets) != 1 || billing.stripeWebhookSecrets[0] != "whsec_billing" {
+		t.Errorf("Expected the webhook secret to NOT be merged, got %v", billing.stripeWebhookSecrets)
+	}
+
+	more := NewClient()
+	more.AddSuccessHandler("invoice.paid", success)
+	if err := billing.Merge(more); err != nil {
+		t.Fatalf("Merge should have NOT failed, got %s", err)
+	}
+	if handlers, _ := billing.successFor("invoice.paid"); len(handlers) != 2 {
+		t.Errorf("Expected the success handlers to be appended, got %d", len(handlers))
+	}
+
+	failure := func(_ *stripe.Event, err error) error {
+		return err
+	}
+	billing.AddFailureHandler("invoice.paid", failure)
+	conflicting := NewClient()
+	conflicting.AppendHandler("refund.created", noop)
+	conflicting.AddFailureHandler("invoice.paid", failure)
+	if err := billing.Merge(conflicting); !errors.Is(err, ErrMergeConflict) {
+		t.Errorf("Expected ErrMergeConflict, got %v", err)
+	}
+	if billing.HasHandler("refund.created") {
+		t.Errorf("Expected a conflicting merge to leave the client unchanged")
+	}
+}
@@ -0,0 +1,43 @@
+package stripetotrello
+
+import (
+	"time"
+)
+
+const (
+	OUTCOME_RECEIVED = "received"
+	OUTCOME_SUCCESS  = "success"
+	OUTCOME_FAILURE  = "failure"
+
+	OUTCOME_UNKNOWN_TYPE = "unknown_type"
+	OUTCOME_UNHANDLED    = "unhandled"
+	OUTCOME_DROPPED      = "dropped"
+	OUTCOME_DEDUPED      = "deduped"
+)
+
+type (
+	// Metrics is called once with OUTCOME_RECEIVED per dispatched event, then
+	// once per handler with OUTCOME_SUCCESS or OUTCOME_FAILURE and its duration.
+	// Events of a type stripe-go does not know are counted as
+	// OUTCOME_UNKNOWN_TYPE, the ones without handlers as OUTCOME_UNHANDLED and
+	// the ones Enqueue rejects with ErrQueueFull as OUTCOME_DROPPED. Events
+	// the Deduper skips are counted as OUTCOME_DEDUPED only.
+	Metrics interface {
+		IncEvent(eventType, outcome string)
+		ObserveDuration(eventType string, d time.Duration)
+	}
+
+	noopMetrics struct{}
+)
+
+func (noopMetrics) IncEvent(string, string)               {}
+func (noopMetrics) ObserveDuration(string, time.Duration) {}
+
+func WithMetrics(m Metrics) func(*Client) {
+	return func(c *Client) {
+		if m == nil {
+			m = noopMetrics{}
+		}
+		c.metrics = m
+	}
+}
@@ -0,0 +1,81 @@
+package stripetotrello
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	stripe "github.com/stripe/stripe-go/v76"
+)
+
+type fakeMetrics struct {
+	mu        sync.Mutex
+	counts    map[string]int
+	durations map[string][]time.Duration
+}
+
+func newFakeMetrics() *fakeMetrics {
+	return &fakeMetrics{
+		counts:    make(map[string]int),
+		durations: make(map[string][]time.Duration),
+	}
+}
+
+func (f *fakeMetrics) IncEvent(eventType, outcome string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.counts[eventType+":"+outcome]++
+}
+
+func (f *fakeMetrics) ObserveDuration(eventType string, d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.durations[eventType] = append(f.durations[eventType], d)
+}
+
+func TestMetrics(t *testing.T) {
+	type testCase struct {
+		key   string
+		count int
+	}
+
+	metrics := newFakeMetrics()
+	client := NewClient(WithMetrics(metrics))
+	client.AppendHandler("customer.created", func(_ *stripe.Event) (EventResponse, error) {
+		time.Sleep(5 * time.Millisecond)
+		return "ok", nil
+	}, func(_ *stripe.Event) (EventResponse, error) {
+		return nil, fmt.Errorf("trello unavailable")
+	}, func(_ *stripe.Event) (EventResponse, error) {
+		return "ok", nil
+	})
+	client.AppendHandler("customer.updated", func(_ *stripe.Event) (EventResponse, error) {
+		return "ok", nil
+	})
+
+	_ = client.HandleParallel(&stripe.Event{Type: "customer.created"})
+	_ = client.Handle(&stripe.Event{Type: "customer.updated"})
+
+	tcs := []testCase{
+		{"customer.created:" + OUTCOME_RECEIVED, 1},
+		{"customer.created:" + OUTCOME_SUCCESS, 2},
+		{"customer.created:" + OUTCOME_FAILURE, 1},
+		{"customer.updated:" + OUTCOME_RECEIVED, 1},
+		{"customer.updated:" + OUTCOME_SUCCESS, 1},
+		{"customer.updated:" + OUTCOME_FAILURE, 0},
+	}
+
+	for _, tc := range tcs {
+		if metrics.counts[tc.key] != tc.count {
+			t.Errorf("Expected %d for %s, got %d", tc.count, tc.key, metrics.counts[tc.key])
+		}
+	}
+
+	if len(metrics.durations["customer.created"]) != 3 {
+		t.Errorf("Expected 3 durations for customer.created, got %d", len(metrics.durations["customer.created"]))
+	}
+	if len(metrics.durations["customer.updated"]) != 1 {
+		t.Errorf("Expected 1 duration for customer.updated, got %d", len(metrics.durations["customer.updated"]))
+	}
+}
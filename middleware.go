@@ -0,0 +1,35 @@
+package stripetotrello
+
+type (
+	// Middleware wraps every handler, it can inject context values, skip the
+	// handler or change its outcome.
+	Middleware func(next StripeEventHandlerCtx) StripeEventHandlerCtx
+)
+
+// Use adds middleware around every handler, including the ones already
+// registered. The first middleware added is the outermost. They run inside
+// the retries, the handler timeout and the panic recovery.
+func (st *Client) Use(mw ...Middleware) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	for _, m := range mw {
+		if m != nil {
+			st.middleware = append(st.middleware, m)
+		}
+	}
+}
+
+func (st *Client) middlewares() []Middleware {
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+
+	return st.middleware
+}
+
+func wrap(h StripeEventHandlerCtx, chain []Middleware) StripeEventHandlerCtx {
+	for i := len(chain) - 1; i >= 0; i-- {
+		h = chain[i](h)
+	}
+	return h
+}
@@ -0,0 +1,65 @@
+package stripetotrello
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	stripe "github.com/stripe/stripe-go/v76"
+)
+
+type middlewareKey struct{}
+
+func TestMiddleware(t *testing.T) {
+	var order []string
+	trace := func(name string) Middleware {
+		return func(next StripeEventHandlerCtx) StripeEventHandlerCtx {
+			return func(ctx context.Context, e *stripe.Event) (EventResponse, error) {
+				order = append(order, name)
+				return next(ctx, e)
+			}
+		}
+	}
+	inject := func(next StripeEventHandlerCtx) StripeEventHandlerCtx {
+		return func(ctx context.Context, e *stripe.Event) (EventResponse, error) {
+			return next(context.WithValue(ctx, middlewareKey{}, "injected"), e)
+		}
+	}
+	errPermanent := errors.New("permanent")
+	rewrite := func(next StripeEventHandlerCtx) StripeEventHandlerCtx {
+		return func(ctx context.Context, e *stripe.Event) (EventResponse, error) {
+			res, err := next(ctx, e)
+			if err != nil {
+				return nil, errPermanent
+			}
+			return res, nil
+		}
+	}
+
+	var seen interface{}
+	client := NewClient()
+	client.AppendHandlerCtx("customer.created", func(ctx context.Context, _ *stripe.Event) (EventResponse, error) {
+		seen = ctx.Value(middlewareKey{})
+		return "ok", nil
+	})
+	client.AppendHandler("customer.deleted", func(_ *stripe.Event) (EventResponse, error) {
+		return nil, errors.New("trello unavailable")
+	})
+	client.Use(trace("outer"), inject)
+	client.Use(trace("inner"), rewrite)
+
+	if err := client.Handle(&stripe.Event{Type: "customer.created"}); err != nil {
+		t.Errorf("Event should have NOT failed, got %s", err)
+	}
+	if seen != "injected" {
+		t.Errorf("Expected the handler to read the injected value, got %v", seen)
+	}
+	if strings.Join(order, ",") != "outer,inner" {
+		t.Errorf("Expected middleware to compose in registration order, got %v", order)
+	}
+
+	if err := client.Handle(&stripe.Event{Type: "customer.deleted"}); !errors.Is(err, errPermanent) {
+		t.Errorf("Expected the middleware to rewrite the error, got %v", err)
+	}
+}
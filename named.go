@@ -0,0 +1,68 @@
+package stripetotrello
+
+import (
+	"context"
+	"fmt"
+
+	stripe "github.com/stripe/stripe-go/v76"
+)
+
+type onlyKey struct{}
+
+// AppendNamedHandler registers a handler like AppendHandler under a name
+// HandleOnly can select it by. Names are unique per event type or pattern,
+// registering one twice fails with ErrDuplicateHandlerName.
+func (st *Client) AppendNamedHandler(eventType string, name string, handler StripeEventHandler) error {
+	if handler == nil {
+		return newError("Client.AppendNamedHandler", []interface{}{eventType, name}, ErrNilHandler)
+	}
+	rh := withContext([]StripeEventHandler{handler})[0]
+	rh.name = name
+	return st.appendHandlers(eventType, 0, nil, rh)
+}
+
+// HandleOnly dispatches the event with Handle to the named handlers only,
+// e.g. to replay an event through the one handler that failed. Like Replay
+// the event bypasses the Deduper. A name no handler of the event type was
+// registered with, or no name at all, fails with ErrNoHandler and runs none
+// of them.
+func (st *Client) HandleOnly(event *stripe.Event, names ...string) error {
+	ctx := context.WithValue(context.Background(), replayKey{}, true)
+	return st.HandleContext(context.WithValue(ctx, onlyKey{}, names), event)
+}
+
+func onlyNames(ctx context.Context) ([]string, bool) {
+	names, ok := ctx.Value(onlyKey{}).([]string)
+	return names, ok
+}
+
+// named keeps the handlers with one of names, in their dispatch order.
+func named(handlers []registeredHandler, names []string) ([]registeredHandler, error) {
+	if len(names) == 0 {
+		return nil, fmt.Errorf("%w: no handler name given", ErrNoHandler)
+	}
+	output := make([]registeredHandler, 0, len(names))
+	for _, name := range names {
+		if !nameTaken(handlers, name) {
+			return nil, fmt.Errorf("%w: no handler named %q", ErrNoHandler, name)
+		}
+	}
+	for _, rh := range handlers {
+		for _, name := range names {
+			if rh.name == name {
+				output = append(output, rh)
+				break
+			}
+		}
+	}
+	return output, nil
+}
+
+func nameTaken(list []registeredHandler, name string) bool {
+	for _, rh := range list {
+		if rh.name == name {
+			return true
+		}
+	}
+	return false
+}
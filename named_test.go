@@ -0,0 +1,87 @@
+package stripetotrello
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	stripe "github.com/stripe/stripe-go/v76"
+)
+
+func TestHandleOnly(t *testing.T) {
+	type testCase struct {
+		name     string
+		names    []string
+		ran      []string
+		sentinel error
+	}
+
+	tcs := []testCase{
+		{"one handler", []string{"trello"}, []string{"trello"}, nil},
+		{"two handlers", []string{"slack", "email"}, []string{"email", "slack"}, nil},
+		{"unknown name", []string{"trello", "sms"}, nil, ErrNoHandler},
+		{"no names", nil, nil, ErrNoHandler},
+	}
+
+	for _, tc := range tcs {
+		var ran []string
+		client := NewClient(WithDeduper(NewMemoryDeduper(10, time.Hour)))
+		for _, name := range []string{"email", "trello", "slack"} {
+			if err := client.AppendNamedHandler("invoice.paid", name, func(_ *stripe.Event) (EventResponse, error) {
+				ran = append(ran, name)
+				return nil, nil
+			}); err != nil {
+				t.Fatalf("Expected %s to register %s, got %s", tc.name, name, err)
+			}
+		}
+
+		event := &stripe.Event{ID: "evt_test", Type: "invoice.paid"}
+		if err := client.Handle(event); err != nil {
+			t.Fatalf("Expected %s to handle the event, got %s", tc.name, err)
+		}
+		ran = nil
+		processed := client.ProcessedCount()
+
+		err := client.HandleOnly(event, tc.names...)
+		if tc.sentinel != nil && client.ProcessedCount() != processed {
+			t.Errorf("Expected %s to NOT count the event as processed", tc.name)
+		}
+		if tc.sentinel != nil && !errors.Is(err, tc.sentinel) {
+			t.Errorf("Expected %s to fail with %s, got %v", tc.name, tc.sentinel, err)
+		}
+		if tc.sentinel == nil && err != nil {
+			t.Errorf("Expected %s to NOT fail, got %s", tc.name, err)
+		}
+		if len(ran) != len(tc.ran) {
+			t.Fatalf("Expected %s to run %v, got %v", tc.name, tc.ran, ran)
+		}
+		for i := range ran {
+			if ran[i] != tc.ran[i] {
+				t.Errorf("Expected %s to run %v in order, got %v", tc.name, tc.ran, ran)
+			}
+		}
+	}
+}
+
+func TestAppendNamedHandlerCollision(t *testing.T) {
+	noop := func(_ *stripe.Event) (EventResponse, error) {
+		return nil, nil
+	}
+
+	client := NewClient()
+	if err := client.AppendNamedHandler("invoice.paid", "trello", noop); err != nil {
+		t.Fatalf("Expected the first registration to NOT fail, got %s", err)
+	}
+	if err := client.AppendNamedHandler("invoice.paid", "trello", noop); !errors.Is(err, ErrDuplicateHandlerName) {
+		t.Errorf("Expected ErrDuplicateHandlerName, got %v", err)
+	}
+	if err := client.AppendNamedHandler("invoice.created", "trello", noop); err != nil {
+		t.Errorf("Expected names to be unique per event type only, got %s", err)
+	}
+	if err := client.AppendNamedHandler("invoice.paid", "slack", nil); !errors.Is(err, ErrNilHandler) {
+		t.Errorf("Expected ErrNilHandler, got %v", err)
+	}
+	if count := client.HandlerCount("invoice.paid"); count != 1 {
+		t.Errorf("Expected 1 invoice.paid handler, got %d", count)
+	}
+}
@@ -0,0 +1,61 @@
+package stripetotrello
+
+import (
+	"context"
+	"sync/atomic"
+
+	stripe "github.com/stripe/stripe-go/v76"
+)
+
+type (
+	// HandleOutcome tells apart the ways Handle can return. Handled is true
+	// once a handler ran, false for events without a handler or skipped by
+	// the mode, the Deduper or the circuit breaker. Recovered is true when a
+	// handler failed but the failure handler resolved it, Err is then nil.
+	// Deduped is true when the Deduper skipped the event as a duplicate.
+	HandleOutcome struct {
+		Handled   bool
+		Recovered bool
+		Deduped   bool
+		Err       error
+	}
+
+	outcomeRecorder struct {
+		handled atomic.Bool
+		failed  atomic.Bool
+		deduped atomic.Bool
+	}
+
+	outcomeRecorderKey struct{}
+)
+
+// HandleWithOutcome dispatches the event like HandleContext and reports
+// whether it was handled and whether a failure was recovered.
+func (st *Client) HandleWithOutcome(ctx context.Context, event *stripe.Event) HandleOutcome {
+	rec := &outcomeRecorder{}
+	err := st.HandleContext(context.WithValue(ctx, outcomeRecorderKey{}, rec), event)
+	return HandleOutcome{
+		Handled:   rec.handled.Load(),
+		Recovered: err == nil && rec.failed.Load(),
+		Deduped:   rec.deduped.Load(),
+		Err:       err,
+	}
+}
+
+func recordOutcome(ctx context.Context, err error) {
+	rec, ok := ctx.Value(outcomeRecorderKey{}).(*outcomeRecorder)
+	if !ok {
+		return
+	}
+
+	rec.handled.Store(true)
+	if err != nil {
+		rec.failed.Store(true)
+	}
+}
+
+func recordDeduped(ctx context.Context) {
+	if rec, ok := ctx.Value(outcomeRecorderKey{}).(*outcomeRecorder); ok {
+		rec.deduped.Store(true)
+	}
+}
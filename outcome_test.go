@@ -0,0 +1,46 @@
+package stripetotrello
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	stripe "github.com/stripe/stripe-go/v76"
+)
+
+func TestHandleWithOutcome(t *testing.T) {
+	type testCase struct {
+		name      string
+		eventType stripe.EventType
+		handled   bool
+		recovered bool
+		failed    bool
+	}
+
+	client := NewClient()
+	client.AppendHandler("customer.created", func(_ *stripe.Event) (EventResponse, error) {
+		return "ok", nil
+	})
+	failing := func(_ *stripe.Event) (EventResponse, error) {
+		return nil, errors.New("test")
+	}
+	client.AppendHandler("customer.updated", failing)
+	client.AppendHandler("customer.deleted", failing)
+	client.AddFailureHandler("customer.updated", func(_ *stripe.Event, _ error) error {
+		return nil
+	})
+
+	tcs := []testCase{
+		{"success", "customer.created", true, false, false},
+		{"swallowed by the failure handler", "customer.updated", true, true, false},
+		{"failure", "customer.deleted", true, false, true},
+		{"no handler", "invoice.paid", false, false, true},
+	}
+
+	for _, tc := range tcs {
+		out := client.HandleWithOutcome(context.Background(), &stripe.Event{ID: "evt_1", Type: tc.eventType})
+		if out.Handled != tc.handled || out.Recovered != tc.recovered || (out.Err != nil) != tc.failed {
+			t.Errorf("%s: Expected handled = %t, recovered = %t, failed = %t got %+v", tc.name, tc.handled, tc.recovered, tc.failed, out)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package stripetotrello
+
+import (
+	"hash/fnv"
+
+	stripe "github.com/stripe/stripe-go/v76"
+)
+
+// WithPartitionKey serializes the events HandleBatch and the queue workers
+// process when f gives them the same key, e.g. the customer ID, so they are
+// handled in the order they came in while events with different keys still
+// run concurrently. Each key is hashed to a single worker. Events with an
+// empty key are spread over the workers without any ordering.
+func WithPartitionKey(f func(event *stripe.Event) string) func(*Client) {
+	return func(c *Client) {
+		c.partitionKey = f
+	}
+}
+
+// partitioner picks the worker of each event, round robin for the ones
+// without a key. It is not safe for concurrent use.
+type partitioner struct {
+	key     func(event *stripe.Event) string
+	workers int
+	next    int
+}
+
+func (p *partitioner) worker(event *stripe.Event) int {
+	if k := p.key(event); k != "" {
+		h := fnv.New32a()
+		h.Write([]byte(k))
+		return int(h.Sum32() % uint32(p.workers))
+	}
+	p.next = (p.next + 1) % p.workers
+	return p.next
+}
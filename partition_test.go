@@ -0,0 +1,92 @@
+package stripetotrello
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	stripe "github.com/stripe/stripe-go/v76"
+)
+
+func partitionedEvents() []*stripe.Event {
+	var events []*stripe.Event
+	for n := 0; n < 10; n++ {
+		for _, customer := range []string{"cus_1", "cus_2", "cus_3"} {
+			events = append(events, &stripe.Event{
+				ID:   fmt.Sprintf("evt_%s_%d", customer, n),
+				Type: "customer.subscription.updated",
+				Data: &stripe.EventData{Object: map[string]interface{}{"customer": customer, "seq": n}},
+			})
+		}
+	}
+	return events
+}
+
+func TestPartitionKey(t *testing.T) {
+	type testCase struct {
+		name    string
+		process func(*Client, []*stripe.Event)
+	}
+
+	tcs := []testCase{
+		{"batch", func(c *Client, events []*stripe.Event) {
+			c.HandleBatch(context.Background(), events)
+		}},
+		{"queue", func(c *Client, events []*stripe.Event) {
+			c.Start(4)
+			for _, event := range events {
+				if err := c.Enqueue(event); err != nil {
+					t.Fatalf("Enqueue should have NOT failed %v", err)
+				}
+			}
+			if err := c.Stop(context.Background()); err != nil {
+				t.Fatalf("Stop should have NOT failed %v", err)
+			}
+		}},
+	}
+
+	for _, tc := range tcs {
+		var mu sync.Mutex
+		order := map[string][]int{}
+		client := NewClient(WithMaxConcurrency(4), WithPartitionKey(func(event *stripe.Event) string {
+			return event.Data.Object["customer"].(string)
+		}))
+		client.AppendHandler("customer.subscription.updated", func(e *stripe.Event) (EventResponse, error) {
+			customer, seq := e.Data.Object["customer"].(string), e.Data.Object["seq"].(int)
+			// Later events of a customer finish faster, reordering them unless
+			// they are serialized.
+			time.Sleep(time.Duration(10-seq) * 100 * time.Microsecond)
+			mu.Lock()
+			order[customer] = append(order[customer], seq)
+			mu.Unlock()
+			return nil, nil
+		})
+
+		tc.process(client, partitionedEvents())
+
+		for customer, seqs := range order {
+			for i, seq := range seqs {
+				if seq != i {
+					t.Errorf("%s: Expected the events of %s in order got %v", tc.name, customer, seqs)
+					break
+				}
+			}
+		}
+		if len(order) != 3 {
+			t.Errorf("%s: Expected 3 customers got %v", tc.name, order)
+		}
+	}
+}
+
+func TestPartitionerSpreadsKeylessEvents(t *testing.T) {
+	p := &partitioner{key: func(*stripe.Event) string { return "" }, workers: 3}
+	seen := map[int]bool{}
+	for i := 0; i < 3; i++ {
+		seen[p.worker(&stripe.Event{})] = true
+	}
+	if len(seen) != 3 {
+		t.Errorf("Expected keyless events on every worker got %v", seen)
+	}
+}
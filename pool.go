@@ -0,0 +1,65 @@
+package stripetotrello
+
+import (
+	"runtime"
+	"sync"
+)
+
+type workerPool struct {
+	jobs chan func()
+	once sync.Once
+}
+
+// WithWorkerPool makes HandleParallel run handlers on a pool of goroutines
+// shared by all the calls instead of starting one per handler. The pool has
+// WithMaxConcurrency workers, GOMAXPROCS when unset, is started by the first
+// call and stopped by Close. A handler that finds every worker busy gets a
+// goroutine of its own, so handlers dispatching events themselves cannot
+// deadlock the pool.
+func WithWorkerPool() func(*Client) {
+	return func(c *Client) {
+		c.workerPool = true
+	}
+}
+
+func newWorkerPool(size int) *workerPool {
+	p := &workerPool{jobs: make(chan func())}
+	for i := 0; i < size; i++ {
+		go func() {
+			for job := range p.jobs {
+				job()
+			}
+		}()
+	}
+	return p
+}
+
+func (st *Client) spawn(job func()) {
+	if !st.workerPool {
+		go job()
+		return
+	}
+
+	st.poolOnce.Do(func() {
+		size := st.maxConcurrency
+		if size <= 0 {
+			size = runtime.GOMAXPROCS(0)
+		}
+		st.pool = newWorkerPool(size)
+	})
+
+	select {
+	case st.pool.jobs <- job:
+	default:
+		go job()
+	}
+}
+
+func (p *workerPool) stop() {
+	if p == nil {
+		return
+	}
+	p.once.Do(func() {
+		close(p.jobs)
+	})
+}
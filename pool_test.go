@@ -0,0 +1,114 @@
+package stripetotrello
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	stripe "github.com/stripe/stripe-go/v76"
+)
+
+func TestWorkerPool(t *testing.T) {
+	var calls atomic.Int32
+	ok := func(_ *stripe.Event) (EventResponse, error) {
+		calls.Add(1)
+		return "ok", nil
+	}
+	failOdd := func(event *stripe.Event) (EventResponse, error) {
+		calls.Add(1)
+		var n int
+		fmt.Sscanf(event.ID, "evt_%d", &n)
+		if n%2 == 1 {
+			return nil, fmt.Errorf("odd event %s", event.ID)
+		}
+		return "ok", nil
+	}
+
+	client := NewClient(WithWorkerPool(), WithMaxConcurrency(2))
+	client.AppendHandler("customer.created", ok, failOdd, ok, ok)
+
+	const events = 50
+	var wg sync.WaitGroup
+	var failed atomic.Int32
+	for i := 0; i < events; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			err := client.HandleParallel(&stripe.Event{ID: fmt.Sprintf("evt_%d", i), Type: "customer.created"})
+			var errs StripeEventErrors
+			switch {
+			case i%2 == 0 && err != nil:
+				t.Errorf("Event should have NOT failed evt_%d %v", i, err)
+			case i%2 == 1 && (!errors.As(err, &errs) || len(errs) != 1):
+				t.Errorf("Expected a single failure for evt_%d got %v", i, err)
+			case err != nil:
+				failed.Add(1)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if got := calls.Load(); got != events*4 {
+		t.Errorf("Expected %d handler calls got %d", events*4, got)
+	}
+	if got := failed.Load(); got != events/2 {
+		t.Errorf("Expected %d failed events got %d", events/2, got)
+	}
+
+	if err := client.Close(context.Background()); err != nil {
+		t.Fatalf("Close should have NOT failed %v", err)
+	}
+	select {
+	case _, open := <-client.pool.jobs:
+		if open {
+			t.Errorf("Expected no job left in the worker pool")
+		}
+	default:
+		t.Errorf("Expected Close to stop the worker pool")
+	}
+}
+
+func TestWorkerPoolNested(t *testing.T) {
+	client := NewClient(WithWorkerPool(), WithMaxConcurrency(1))
+	client.AppendHandlerCtx("customer.updated", func(_ context.Context, _ *stripe.Event) (EventResponse, error) {
+		return nil, nil
+	})
+	client.AppendHandlerCtx("customer.created", func(ctx context.Context, event *stripe.Event) (EventResponse, error) {
+		return nil, client.HandleParallelContext(ctx, &stripe.Event{ID: event.ID + "_nested", Type: "customer.updated"})
+	})
+
+	if err := client.HandleParallel(&stripe.Event{ID: "evt_1", Type: "customer.created"}); err != nil {
+		t.Errorf("Event should have NOT failed %v", err)
+	}
+}
+
+func BenchmarkHandleParallel(b *testing.B) {
+	noop := func(_ *stripe.Event) (EventResponse, error) {
+		return nil, nil
+	}
+
+	for _, pooled := range []bool{false, true} {
+		b.Run(fmt.Sprintf("pooled=%t", pooled), func(b *testing.B) {
+			cfgs := []func(*Client){WithMaxConcurrency(8)}
+			if pooled {
+				cfgs = append(cfgs, WithWorkerPool())
+			}
+			client := NewClient(cfgs...)
+			client.AppendHandler("customer.created", noop, noop, noop, noop, noop, noop, noop, noop)
+			event := &stripe.Event{ID: "evt_1", Type: "customer.created"}
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					if err := client.HandleParallel(event); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		})
+	}
+}
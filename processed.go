@@ -0,0 +1,36 @@
+package stripetotrello
+
+import (
+	"time"
+
+	stripe "github.com/stripe/stripe-go/v76"
+)
+
+type processedEvent struct {
+	id string
+	at time.Time
+}
+
+// LastProcessed returns the id of the last event Handle or HandleParallel
+// dispatched successfully and when it finished, zero values before the first
+// one.
+func (st *Client) LastProcessed() (eventID string, at time.Time) {
+	last := st.last.Load()
+	if last == nil {
+		return "", time.Time{}
+	}
+	return last.id, last.at
+}
+
+// ProcessedCount returns how many events were dispatched successfully.
+func (st *Client) ProcessedCount() uint64 {
+	return st.processed.Load()
+}
+
+func (st *Client) markProcessed(event *stripe.Event, err error) {
+	if err != nil {
+		return
+	}
+	st.last.Store(&processedEvent{id: event.ID, at: st.clock.Now()})
+	st.processed.Add(1)
+}
@@ -0,0 +1,37 @@
+package stripetotrello
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	stripe "github.com/stripe/stripe-go/v76"
+)
+
+func TestLastProcessed(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)}
+	client := NewClient(WithClock(clock))
+	client.AppendHandler("customer.created", func(_ *stripe.Event) (EventResponse, error) {
+		return "ok", nil
+	})
+	client.AppendHandler("customer.deleted", func(_ *stripe.Event) (EventResponse, error) {
+		return nil, errors.New("trello unavailable")
+	})
+
+	if id, at := client.LastProcessed(); id != "" || !at.IsZero() || client.ProcessedCount() != 0 {
+		t.Errorf("Expected nothing processed yet, got %s at %s", id, at)
+	}
+
+	client.Handle(&stripe.Event{ID: "evt_1", Type: "customer.created"})
+	clock.now = clock.now.Add(time.Minute)
+	client.HandleParallel(&stripe.Event{ID: "evt_2", Type: "customer.created"})
+	client.Handle(&stripe.Event{ID: "evt_3", Type: "customer.deleted"})
+	client.Handle(&stripe.Event{ID: "evt_4", Type: "customer.updated"})
+
+	if n := client.ProcessedCount(); n != 2 {
+		t.Errorf("Expected 2 processed events, got %d", n)
+	}
+	if id, at := client.LastProcessed(); id != "evt_2" || !at.Equal(clock.now) {
+		t.Errorf("Expected evt_2 at %s, got %s at %s", clock.now, id, at)
+	}
+}
@@ -0,0 +1,130 @@
+package stripetotrello
+
+import (
+	"context"
+	"errors"
+
+	stripe "github.com/stripe/stripe-go/v76"
+)
+
+const QUEUE_SIZE = 100
+
+// WithQueueSize sets how many events Enqueue buffers before rejecting them
+// with ErrQueueFull.
+func WithQueueSize(size int) func(*Client) {
+	return func(c *Client) {
+		if size >= 0 {
+			c.queueSize = size
+		}
+	}
+}
+
+// WithOnDrop sets a callback for the events Enqueue rejects because the
+// buffer is full, they are also counted as OUTCOME_DROPPED.
+func WithOnDrop(f func(event *stripe.Event)) func(*Client) {
+	return func(c *Client) {
+		c.onDrop = f
+	}
+}
+
+// Enqueue buffers the event for the workers launched by Start. It never
+// blocks, a full buffer fails with ErrQueueFull so callers can apply
+// backpressure, e.g. by answering 503.
+func (st *Client) Enqueue(event *stripe.Event) error {
+	err := st.enqueue(event)
+	if errors.Is(err, ErrQueueFull) {
+		st.metrics.IncEvent(string(event.Type), OUTCOME_DROPPED)
+		// Called without holding the queue lock so the callback can enqueue
+		// elsewhere or retry.
+		if st.onDrop != nil {
+			st.onDrop(event)
+		}
+	}
+	return err
+}
+
+func (st *Client) enqueue(event *stripe.Event) error {
+	st.queueMu.Lock()
+	defer st.queueMu.Unlock()
+
+	if st.queueStopped {
+		return newError("Client.Enqueue", []interface{}{event}, ErrQueueStopped)
+	}
+
+	select {
+	case st.queue <- event:
+		return nil
+	default:
+		return newError("Client.Enqueue", []interface{}{event}, ErrQueueFull)
+	}
+}
+
+// Start launches workers goroutines that pass queued events to Handle. With
+// WithPartitionKey the events are routed to the workers by key, Start should
+// then be called only once.
+func (st *Client) Start(workers int) {
+	if st.partitionKey != nil && workers > 1 {
+		st.startPartitioned(workers)
+		return
+	}
+	for i := 0; i < workers; i++ {
+		st.workers.Add(1)
+		go st.work(st.queue)
+	}
+}
+
+func (st *Client) startPartitioned(workers int) {
+	queues := make([]chan *stripe.Event, workers)
+	for w := range queues {
+		queues[w] = make(chan *stripe.Event, st.queueSize/workers+1)
+		st.workers.Add(1)
+		go st.work(queues[w])
+	}
+
+	st.workers.Add(1)
+	go func() {
+		defer st.workers.Done()
+
+		p := &partitioner{key: st.partitionKey, workers: workers}
+		for event := range st.queue {
+			queues[p.worker(event)] <- event
+		}
+		for _, q := range queues {
+			close(q)
+		}
+	}()
+}
+
+func (st *Client) work(queue <-chan *stripe.Event) {
+	defer st.workers.Done()
+
+	for event := range queue {
+		if _, err := st.HandleCollect(event); err != nil {
+			st.logger.Error("queued event failed", st.logFields(context.Background(), event, "error", err)...)
+		}
+	}
+}
+
+// Stop rejects further Enqueue calls and waits for the workers to drain the
+// buffered events or for ctx to expire.
+func (st *Client) Stop(ctx context.Context) error {
+	st.queueMu.Lock()
+	if !st.queueStopped {
+		st.queueStopped = true
+		close(st.queue)
+	}
+	st.queueMu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		st.workers.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
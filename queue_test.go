@@ -0,0 +1,95 @@
+package stripetotrello
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	stripe "github.com/stripe/stripe-go/v76"
+)
+
+func TestQueueDrain(t *testing.T) {
+	var handled int32
+
+	client := NewClient(WithQueueSize(10))
+	client.AppendHandler("customer.created", func(_ *stripe.Event) (EventResponse, error) {
+		atomic.AddInt32(&handled, 1)
+		return nil, nil
+	})
+
+	for i := 0; i < 10; i++ {
+		if err := client.Enqueue(&stripe.Event{Type: "customer.created"}); err != nil {
+			t.Fatalf("Expected event %d to be queued, got %s", i, err)
+		}
+	}
+
+	client.Start(3)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := client.Stop(ctx); err != nil {
+		t.Fatalf("Expected Stop to drain the queue, got %s", err)
+	}
+
+	if got := atomic.LoadInt32(&handled); got != 10 {
+		t.Errorf("Expected 10 handled events, got %d", got)
+	}
+
+	if err := client.Enqueue(&stripe.Event{Type: "customer.created"}); !errors.Is(err, ErrQueueStopped) {
+		t.Errorf("Expected ErrQueueStopped after Stop, got %v", err)
+	}
+}
+
+func TestQueueFull(t *testing.T) {
+	client := NewClient(WithQueueSize(2))
+
+	for i := 0; i < 2; i++ {
+		if err := client.Enqueue(&stripe.Event{Type: "customer.created"}); err != nil {
+			t.Fatalf("Expected event %d to be queued, got %s", i, err)
+		}
+	}
+
+	err := client.Enqueue(&stripe.Event{Type: "customer.created"})
+	if !errors.Is(err, ErrQueueFull) {
+		t.Errorf("Expected ErrQueueFull, got %v", err)
+	}
+	if _, ok := err.(StripeEventError); !ok {
+		t.Errorf("Expected a StripeEventError, got %T", err)
+	}
+}
+
+func TestQueueOnDrop(t *testing.T) {
+	var dropped []*stripe.Event
+	metrics := newFakeMetrics()
+	client := NewClient(WithQueueSize(2), WithMetrics(metrics), WithOnDrop(func(event *stripe.Event) {
+		dropped = append(dropped, event)
+	}))
+
+	events := []*stripe.Event{
+		{ID: "evt_1", Type: "customer.created"},
+		{ID: "evt_2", Type: "customer.created"},
+		{ID: "evt_3", Type: "customer.created"},
+		{ID: "evt_4", Type: "invoice.paid"},
+	}
+	for _, event := range events {
+		client.Enqueue(event)
+	}
+
+	if len(dropped) != 2 || dropped[0] != events[2] || dropped[1] != events[3] {
+		t.Fatalf("Expected the drop callback to get the rejected events, got %v", dropped)
+	}
+	if metrics.counts["customer.created:"+OUTCOME_DROPPED] != 1 || metrics.counts["invoice.paid:"+OUTCOME_DROPPED] != 1 {
+		t.Errorf("Expected a dropped count per rejected event, got %v", metrics.counts)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	client.Start(1)
+	client.Stop(ctx)
+
+	if err := client.Enqueue(&stripe.Event{Type: "customer.created"}); !errors.Is(err, ErrQueueStopped) || len(dropped) != 2 {
+		t.Errorf("Expected events rejected after Stop to NOT be reported as dropped, got %v", err)
+	}
+}
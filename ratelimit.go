@@ -0,0 +1,23 @@
+package stripetotrello
+
+import (
+	"github.com/skipper-digital-studio/stripetotrello/internal/ratelimit"
+)
+
+// WithRateLimit lets at most rps handlers of an event type or pattern start
+// per second, across all events, with bursts of up to burst handlers.
+// Handlers wait for their turn until their context is done.
+func WithRateLimit(eventType string, rps float64, burst int) func(*Client) {
+	return func(c *Client) {
+		if rps <= 0 {
+			return
+		}
+		if burst < 1 {
+			burst = 1
+		}
+		if c.rateLimits == nil {
+			c.rateLimits = make(map[string]*ratelimit.Bucket)
+		}
+		c.rateLimits[eventType] = ratelimit.New(rps, float64(burst))
+	}
+}
@@ -0,0 +1,117 @@
+package stripetotrello
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	stripe "github.com/stripe/stripe-go/v76"
+)
+
+// steppingClock only moves when a bucket sleeps on it, so the waits are
+// known exactly.
+type steppingClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	sleeps []time.Duration
+}
+
+func (c *steppingClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *steppingClock) sleep(ctx context.Context, d time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	c.sleeps = append(c.sleeps, d)
+	return nil
+}
+
+func (c *steppingClock) slept() time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var total time.Duration
+	for _, d := range c.sleeps {
+		total += d
+	}
+	return total
+}
+
+func rateLimitedClient(clock *steppingClock, cfgs ...func(*Client)) *Client {
+	client := NewClient(append(cfgs, WithClock(clock))...)
+	for _, b := range client.rateLimits {
+		b.Sleep = clock.sleep
+	}
+	return client
+}
+
+func TestRateLimit(t *testing.T) {
+	clock := &steppingClock{now: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)}
+	client := rateLimitedClient(clock, WithRateLimit("customer.*", 50, 5))
+
+	calls := 0
+	client.AppendHandler("customer.created", func(_ *stripe.Event) (EventResponse, error) {
+		calls++
+		return "ok", nil
+	})
+	client.AppendHandler("invoice.paid", func(_ *stripe.Event) (EventResponse, error) {
+		return "ok", nil
+	})
+
+	for i := 0; i < 25; i++ {
+		client.Handle(&stripe.Event{Type: "customer.created"})
+	}
+	// 5 handlers run on the burst, the other 20 wait 20ms each at 50 per second.
+	if slept := clock.slept(); slept < 399*time.Millisecond || slept > 401*time.Millisecond {
+		t.Errorf("Expected 25 handlers to wait 400ms at 50 rps with a burst of 5, waited %s", slept)
+	}
+	if calls != 25 {
+		t.Errorf("Expected 25 handler calls, got %d", calls)
+	}
+
+	waits := len(clock.sleeps)
+	for i := 0; i < 25; i++ {
+		client.Handle(&stripe.Event{Type: "invoice.paid"})
+	}
+	if len(clock.sleeps) != waits {
+		t.Errorf("Expected other event types to NOT be limited, waited %d more times", len(clock.sleeps)-waits)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := client.HandleContext(ctx, &stripe.Event{Type: "customer.created"}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected waiting for a token to respect the context, got %v", err)
+	}
+}
+
+func TestRateLimitRetries(t *testing.T) {
+	clock := &steppingClock{now: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)}
+	client := rateLimitedClient(clock, WithRateLimit("customer.*", 10, 1), WithHandlerRetry(3, nil))
+
+	attempts := 0
+	cause := errors.New("trello unavailable")
+	client.AppendHandler("customer.created", func(_ *stripe.Event) (EventResponse, error) {
+		attempts++
+		return nil, cause
+	})
+
+	if err := client.Handle(&stripe.Event{Type: "customer.created"}); !errors.Is(err, cause) {
+		t.Errorf("Expected the handler error, got %v", err)
+	}
+	if attempts != 3 {
+		t.Errorf("Expected 3 attempts, got %d", attempts)
+	}
+	// The first attempt runs on the burst, each retry waits for a token.
+	if len(clock.sleeps) != 2 || clock.slept() < 199*time.Millisecond || clock.slept() > 201*time.Millisecond {
+		t.Errorf("Expected each retry to wait 100ms for a token, got %v", clock.sleeps)
+	}
+}
@@ -0,0 +1,58 @@
+package stripetotrello
+
+import (
+	"context"
+
+	stripe "github.com/stripe/stripe-go/v76"
+)
+
+// RawEvent keeps the exact bytes and signature header Stripe sent next to the
+// decoded event, so a failed event can be stored and replayed later.
+type RawEvent struct {
+	*stripe.Event
+	Raw       []byte
+	Signature string
+}
+
+// EventWithRaw verifies and decodes the payload like Event, retaining raw and
+// signature.
+func (st *Client) EventWithRaw(raw []byte, signature string) (*RawEvent, error) {
+	event, err := st.Event(raw, signature)
+	if err != nil {
+		return nil, err
+	}
+	return &RawEvent{Event: event, Raw: raw, Signature: signature}, nil
+}
+
+// HandleRawEvent dispatches the event with Handle. While it runs, failure
+// handlers can get the original payload back with RawEventFor and dead
+// letter stores implementing RawDeadLetter receive it.
+func (st *Client) HandleRawEvent(event *RawEvent) error {
+	return st.HandleRawEventContext(context.Background(), event)
+}
+
+// HandleRawEventContext dispatches the event like HandleRawEvent with the
+// handlers getting ctx, as HandleContext does.
+func (st *Client) HandleRawEventContext(ctx context.Context, event *RawEvent) error {
+	st.raws.Store(event.Event, event)
+	defer st.raws.Delete(event.Event)
+
+	return st.HandleContext(ctx, event.Event)
+}
+
+func (st *Client) HandleRawEventParallel(event *RawEvent) error {
+	st.raws.Store(event.Event, event)
+	defer st.raws.Delete(event.Event)
+
+	return st.HandleParallel(event.Event)
+}
+
+// RawEventFor returns the payload of an event being dispatched by
+// HandleRawEvent or HandleRawEventParallel.
+func (st *Client) RawEventFor(event *stripe.Event) (*RawEvent, bool) {
+	v, ok := st.raws.Load(event)
+	if !ok {
+		return nil, false
+	}
+	return v.(*RawEvent), true
+}
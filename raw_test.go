@@ -0,0 +1,70 @@
+package stripetotrello
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/skipper-digital-studio/stripetotrello/testutil"
+	stripe "github.com/stripe/stripe-go/v76"
+)
+
+func TestRawEventReplay(t *testing.T) {
+	payload := testutil.Payload("customer.created")
+	sig := signature(testSecret, payload, time.Now())
+
+	dl := NewMemoryDeadLetter()
+	client := NewClient(WithStripeWebhookSecret(testSecret), WithDeadLetter(dl))
+
+	calls := 0
+	client.AppendHandler("customer.created", func(_ *stripe.Event) (EventResponse, error) {
+		calls++
+		if calls == 1 {
+			return nil, errors.New("trello unavailable")
+		}
+		return "ok", nil
+	})
+
+	var failedRaw []byte
+	client.AddFailureHandler("customer.created", func(e *stripe.Event, err error) error {
+		if raw, ok := client.RawEventFor(e); ok {
+			failedRaw = raw.Raw
+		}
+		return err
+	})
+
+	event, err := client.EventWithRaw(payload, sig)
+	if err != nil {
+		t.Fatalf("Event should have NOT failed, got %s", err)
+	}
+	if !bytes.Equal(event.Raw, payload) || event.Signature != sig {
+		t.Errorf("Expected the raw payload and signature to be retained")
+	}
+
+	if err := client.HandleRawEvent(event); err == nil {
+		t.Fatalf("Expected the first dispatch to fail")
+	}
+	if !bytes.Equal(failedRaw, payload) {
+		t.Errorf("Expected the failure handler to get the raw payload, got %q", failedRaw)
+	}
+	if _, ok := client.RawEventFor(event.Event); ok {
+		t.Errorf("Expected the raw payload to be released after dispatch")
+	}
+
+	entries := dl.Entries()
+	if len(entries) != 1 || !bytes.Equal(entries[0].Raw, payload) {
+		t.Fatalf("Expected the raw payload in the dead letter store, got %v", entries)
+	}
+
+	replayed, err := client.EventWithRaw(entries[0].Raw, sig)
+	if err != nil {
+		t.Fatalf("Replayed event should have NOT failed, got %s", err)
+	}
+	if err := client.HandleRawEvent(replayed); err != nil {
+		t.Errorf("Replayed event should have NOT failed, got %s", err)
+	}
+	if replayed.ID != event.ID || calls != 2 {
+		t.Errorf("Expected the replay to dispatch %s again, got %s after %d calls", event.ID, replayed.ID, calls)
+	}
+}
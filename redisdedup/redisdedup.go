@@ -0,0 +1,57 @@
+package redisdedup
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const KEY_PREFIX = "stripetotrello:event:"
+
+type (
+	// RedisDeduper implements stripetotrello.Deduper on top of SET NX EX, so
+	// replicas sharing the same redis agree on which one processes an event.
+	// It is a stripetotrello.Forgetter, the key of an event whose handlers
+	// failed is deleted so the Stripe retry is processed by any replica.
+	RedisDeduper struct {
+		client redis.Cmdable
+		ttl    time.Duration
+	}
+
+	RedisDeduperError struct {
+		id  string
+		err error
+	}
+)
+
+func (r RedisDeduperError) Error() string {
+	return fmt.Sprintf("Error checking event %s in redis - result in error %s", r.id, r.err.Error())
+}
+
+func (r RedisDeduperError) Unwrap() error {
+	return r.err
+}
+
+func NewRedisDeduper(client redis.Cmdable, ttl time.Duration) *RedisDeduper {
+	return &RedisDeduper{
+		client: client,
+		ttl:    ttl,
+	}
+}
+
+func (r *RedisDeduper) Seen(id string) (bool, error) {
+	stored, err := r.client.SetNX(context.Background(), KEY_PREFIX+id, time.Now().Unix(), r.ttl).Result()
+	if err != nil {
+		return false, RedisDeduperError{id, err}
+	}
+	return !stored, nil
+}
+
+func (r *RedisDeduper) Forget(id string) error {
+	if err := r.client.Del(context.Background(), KEY_PREFIX+id).Err(); err != nil {
+		return RedisDeduperError{id, err}
+	}
+	return nil
+}
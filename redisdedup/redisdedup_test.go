@@ -0,0 +1,119 @@
+package redisdedup
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	stripe "github.com/stripe/stripe-go/v76"
+
+	"github.com/skipper-digital-studio/stripetotrello"
+)
+
+func TestRedisDeduper(t *testing.T) {
+	type testCase struct {
+		deduper *RedisDeduper
+		id      string
+		seen    bool
+	}
+
+	srv := miniredis.RunT(t)
+	first := NewRedisDeduper(redis.NewClient(&redis.Options{Addr: srv.Addr()}), time.Minute)
+	second := NewRedisDeduper(redis.NewClient(&redis.Options{Addr: srv.Addr()}), time.Minute)
+
+	tcs := []testCase{
+		{first, "evt_1", false},
+		{second, "evt_1", true},
+		{second, "evt_2", false},
+		{first, "evt_2", true},
+	}
+
+	for _, tc := range tcs {
+		seen, err := tc.deduper.Seen(tc.id)
+		if err != nil {
+			t.Errorf("Expected no error for id = %s, got %s", tc.id, err)
+		}
+		if seen != tc.seen {
+			t.Errorf("Expected seen = %t for id = %s, got %t", tc.seen, tc.id, seen)
+		}
+	}
+
+	srv.FastForward(2 * time.Minute)
+	if seen, _ := second.Seen("evt_1"); seen {
+		t.Errorf("Expected evt_1 to expire after the ttl")
+	}
+}
+
+func TestRedisDeduperWithClient(t *testing.T) {
+	srv := miniredis.RunT(t)
+
+	calls := 0
+	replicas := make([]*stripetotrello.Client, 2)
+	for i := range replicas {
+		replicas[i] = stripetotrello.NewClient(stripetotrello.WithDeduper(
+			NewRedisDeduper(redis.NewClient(&redis.Options{Addr: srv.Addr()}), time.Minute),
+		))
+		replicas[i].AppendHandler("customer.created", func(_ *stripe.Event) (stripetotrello.EventResponse, error) {
+			calls++
+			return nil, nil
+		})
+	}
+
+	for _, replica := range replicas {
+		if err := replica.Handle(&stripe.Event{ID: "evt_1", Type: "customer.created"}); err != nil {
+			t.Errorf("Event should have NOT failed, got %s", err)
+		}
+	}
+
+	if calls != 1 {
+		t.Errorf("Expected the event to be processed once across replicas, got %d", calls)
+	}
+}
+
+func TestRedisDeduperForget(t *testing.T) {
+	srv := miniredis.RunT(t)
+
+	calls := 0
+	replicas := make([]*stripetotrello.Client, 2)
+	for i := range replicas {
+		replicas[i] = stripetotrello.NewClient(stripetotrello.WithDeduper(
+			NewRedisDeduper(redis.NewClient(&redis.Options{Addr: srv.Addr()}), time.Minute),
+		))
+		replicas[i].AppendHandler("customer.created", func(_ *stripe.Event) (stripetotrello.EventResponse, error) {
+			calls++
+			if calls == 1 {
+				return nil, errors.New("trello is down")
+			}
+			return nil, nil
+		})
+	}
+
+	if err := replicas[0].Handle(&stripe.Event{ID: "evt_1", Type: "customer.created"}); err == nil {
+		t.Errorf("Event should have failed")
+	}
+	if srv.Exists(KEY_PREFIX + "evt_1") {
+		t.Errorf("Expected the key of a failed event to be deleted")
+	}
+	if err := replicas[1].Handle(&stripe.Event{ID: "evt_1", Type: "customer.created"}); err != nil {
+		t.Errorf("Event should have NOT failed, got %s", err)
+	}
+	if err := replicas[0].Handle(&stripe.Event{ID: "evt_1", Type: "customer.created"}); err != nil {
+		t.Errorf("Event should have NOT failed, got %s", err)
+	}
+
+	if calls != 2 {
+		t.Errorf("Expected the retry to run once more across replicas, got %d calls", calls)
+	}
+}
+
+func TestRedisDeduperError(t *testing.T) {
+	srv := miniredis.RunT(t)
+	deduper := NewRedisDeduper(redis.NewClient(&redis.Options{Addr: srv.Addr()}), time.Minute)
+	srv.Close()
+
+	if _, err := deduper.Seen("evt_1"); err == nil {
+		t.Errorf("Expected an error when redis is unavailable")
+	}
+}
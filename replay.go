@@ -0,0 +1,44 @@
+package stripetotrello
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	stripe "github.com/stripe/stripe-go/v76"
+)
+
+type replayKey struct{}
+
+// Replay dispatches a stored payload with Handle WITHOUT verifying its
+// signature, raw must come from a trusted source such as a dead letter store
+// fed by RawDeadLetter. The event bypasses the Deduper since its id was
+// already recorded when it first arrived.
+func (st *Client) Replay(raw []byte) error {
+	return st.handleUnverified(context.WithValue(context.Background(), replayKey{}, true), "Client.Replay", raw)
+}
+
+// HandleJSON dispatches a JSON event with Handle WITHOUT verifying any
+// signature, for events already verified upstream such as the ones read from
+// an internal queue. Unlike Replay the event goes through the Deduper, it is
+// seen for the first time.
+func (st *Client) HandleJSON(raw []byte) error {
+	return st.handleUnverified(context.Background(), "Client.HandleJSON", raw)
+}
+
+func (st *Client) handleUnverified(ctx context.Context, fn string, raw []byte) error {
+	var event stripe.Event
+	if err := json.Unmarshal(raw, &event); err != nil {
+		return newError(fn, []interface{}{raw}, fmt.Errorf("%w: %w", ErrPayloadParse, err))
+	}
+
+	st.raws.Store(&event, &RawEvent{Event: &event, Raw: raw})
+	defer st.raws.Delete(&event)
+
+	return st.HandleContext(ctx, &event)
+}
+
+func isReplay(ctx context.Context) bool {
+	replay, _ := ctx.Value(replayKey{}).(bool)
+	return replay
+}
@@ -0,0 +1,87 @@
+package stripetotrello
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/skipper-digital-studio/stripetotrello/testutil"
+	stripe "github.com/stripe/stripe-go/v76"
+)
+
+func TestReplay(t *testing.T) {
+	payload := testutil.Payload("customer.created")
+
+	dl := NewMemoryDeadLetter()
+	client := NewClient(WithStripeWebhookSecret(testSecret), WithDeadLetter(dl), WithDeduper(NewMemoryDeduper(10, 0)))
+
+	var fired []string
+	client.AppendHandler("customer.created", func(e *stripe.Event) (EventResponse, error) {
+		fired = append(fired, e.ID)
+		if len(fired) == 1 {
+			return nil, errors.New("trello unavailable")
+		}
+		return "ok", nil
+	})
+
+	if err := client.HandleRaw(payload, signature(testSecret, payload, time.Now())); err == nil {
+		t.Fatalf("Expected the first dispatch to fail")
+	}
+
+	entries := dl.Entries()
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 dead letter, got %d", len(entries))
+	}
+
+	if err := client.Replay(entries[0].Raw); err != nil {
+		t.Errorf("Replay should have NOT failed, got %s", err)
+	}
+	if len(fired) != 2 || fired[1] != "evt_test" {
+		t.Errorf("Expected the replay to fire the handler for evt_test, got %v", fired)
+	}
+
+	if err := client.Replay([]byte("{")); !errors.Is(err, ErrPayloadParse) {
+		t.Errorf("Expected ErrPayloadParse for an invalid payload, got %v", err)
+	}
+}
+
+func TestHandleJSON(t *testing.T) {
+	type testCase struct {
+		name       string
+		raw        []byte
+		shouldFail bool
+		fired      int
+	}
+
+	client := NewClient(WithStripeWebhookSecret(testSecret), WithDeduper(NewMemoryDeduper(10, 0)))
+	var fired int
+	client.AppendHandler("customer.created", func(e *stripe.Event) (EventResponse, error) {
+		if raw, ok := client.RawEventFor(e); !ok || len(raw.Raw) == 0 {
+			t.Errorf("Expected the raw payload to be available to the handler")
+		}
+		fired++
+		return "ok", nil
+	})
+
+	tcs := []testCase{
+		{"plain json", testutil.Payload("customer.created"), false, 1},
+		{"duplicate", testutil.Payload("customer.created"), false, 1},
+		{"malformed", []byte(`{"id": `), true, 1},
+	}
+
+	for _, tc := range tcs {
+		err := client.HandleJSON(tc.raw)
+		if err != nil && !tc.shouldFail {
+			t.Errorf("%s: Event should have NOT failed %v", tc.name, err)
+		}
+		if err == nil && tc.shouldFail {
+			t.Errorf("%s: Event should have failed", tc.name)
+		}
+		if tc.shouldFail && !errors.Is(err, ErrPayloadParse) {
+			t.Errorf("%s: Expected ErrPayloadParse got %v", tc.name, err)
+		}
+		if fired != tc.fired {
+			t.Errorf("%s: Expected %d handler calls got %d", tc.name, tc.fired, fired)
+		}
+	}
+}
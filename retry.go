@@ -0,0 +1,60 @@
+package stripetotrello
+
+import (
+	"errors"
+)
+
+type (
+	// RetryableError is implemented by handler errors that know whether
+	// running the handler again can succeed, e.g. true for a rate limited
+	// call and false for a rejected request.
+	RetryableError interface {
+		error
+		Retryable() bool
+	}
+
+	retryableError struct {
+		err       error
+		retryable bool
+	}
+)
+
+// MarkRetryable wraps err so WithHandlerRetry runs the handler again.
+func MarkRetryable(err error) error {
+	if err == nil {
+		return nil
+	}
+	return retryableError{err: err, retryable: true}
+}
+
+// MarkPermanent wraps err so WithHandlerRetry gives up on the first attempt
+// and the event goes straight to the failure handler and the DeadLetter.
+func MarkPermanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return retryableError{err: err, retryable: false}
+}
+
+func (r retryableError) Error() string {
+	return r.err.Error()
+}
+
+func (r retryableError) Unwrap() error {
+	return r.err
+}
+
+func (r retryableError) Retryable() bool {
+	return r.retryable
+}
+
+// IsRetryable reports whether err is worth retrying, the outermost
+// RetryableError in its chain decides. Errors that do not implement it are
+// retryable, as they were before RetryableError existed.
+func IsRetryable(err error) bool {
+	var r RetryableError
+	if errors.As(err, &r) {
+		return r.Retryable()
+	}
+	return true
+}
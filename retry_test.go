@@ -0,0 +1,58 @@
+package stripetotrello
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	stripe "github.com/stripe/stripe-go/v76"
+)
+
+func TestRetryableErrors(t *testing.T) {
+	type testCase struct {
+		name      string
+		err       error
+		cause     error
+		attempts  int
+		retryable bool
+	}
+
+	errBadRequest := errors.New("400 bad request")
+	errRateLimited := errors.New("429 too many requests")
+	tcs := []testCase{
+		{"permanent", MarkPermanent(errBadRequest), errBadRequest, 1, false},
+		{"retryable", MarkRetryable(errRateLimited), errRateLimited, 3, true},
+		{"unmarked", errRateLimited, errRateLimited, 3, true},
+		{"wrapped permanent", fmt.Errorf("create card: %w", MarkPermanent(errBadRequest)), errBadRequest, 1, false},
+	}
+
+	for _, tc := range tcs {
+		attempts := 0
+		deadLetter := NewMemoryDeadLetter()
+		client := NewClient(WithHandlerRetry(3, nil), WithDeadLetter(deadLetter))
+		client.AppendHandler("invoice.paid", func(_ *stripe.Event) (EventResponse, error) {
+			attempts++
+			return nil, tc.err
+		})
+
+		err := client.Handle(&stripe.Event{ID: "evt_test", Type: "invoice.paid"})
+		if !errors.Is(err, tc.cause) {
+			t.Errorf("Expected %s to fail with the handler error, got %v", tc.name, err)
+		}
+		if attempts != tc.attempts {
+			t.Errorf("Expected %s to run %d times, ran %d", tc.name, tc.attempts, attempts)
+		}
+		if IsRetryable(err) != tc.retryable {
+			t.Errorf("Expected %s to be retryable = %t, got %v", tc.name, tc.retryable, err)
+		}
+
+		entries := deadLetter.Entries()
+		if len(entries) != 1 || entries[0].Retryable != tc.retryable {
+			t.Errorf("Expected %s to be dead lettered with retryable = %t, got %+v", tc.name, tc.retryable, entries)
+		}
+	}
+
+	if MarkRetryable(nil) != nil || MarkPermanent(nil) != nil {
+		t.Errorf("Expected marking a nil error to return nil")
+	}
+}
@@ -0,0 +1,34 @@
+package stripetotrello
+
+import (
+	"context"
+
+	stripe "github.com/stripe/stripe-go/v76"
+)
+
+// WithScopeFunc makes Handle call f once the handlers of an event are known
+// and before the first one runs, e.g. to begin a database transaction and
+// put it in the context the handlers get. The returned teardown is called
+// with the final error of the event, after the success or failure handlers,
+// to commit or roll back, or with ErrHandlerPanic when a handler panics
+// without WithPanicRecovery. An error from f fails the event without running
+// any handler. HandleParallel does not call f.
+func WithScopeFunc(f func(ctx context.Context, event *stripe.Event) (context.Context, func(err error), error)) func(*Client) {
+	return func(c *Client) {
+		c.scopeFunc = f
+	}
+}
+
+func (st *Client) scope(ctx context.Context, event *stripe.Event) (context.Context, func(err error), error) {
+	if st.scopeFunc == nil {
+		return ctx, func(error) {}, nil
+	}
+	scoped, teardown, err := st.scopeFunc(ctx, event)
+	if err != nil {
+		return ctx, nil, err
+	}
+	if teardown == nil {
+		teardown = func(error) {}
+	}
+	return scoped, teardown, nil
+}
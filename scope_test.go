@@ -0,0 +1,107 @@
+package stripetotrello
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	stripe "github.com/stripe/stripe-go/v76"
+)
+
+type scopeKey struct{}
+
+func TestWithScopeFunc(t *testing.T) {
+	type testCase struct {
+		name     string
+		pattern  string
+		scopeErr error
+		sentinel error
+	}
+
+	errHandler := errors.New("handler failed")
+	errBegin := errors.New("begin failed")
+	tcs := []testCase{
+		{"success", "customer.created", nil, nil},
+		{"handler error", "customer.deleted", nil, errHandler},
+		{"scope error", "customer.created", errBegin, errBegin},
+	}
+
+	for _, tc := range tcs {
+		var torndown []error
+		var scoped []interface{}
+		client := NewClient(WithScopeFunc(func(ctx context.Context, event *stripe.Event) (context.Context, func(err error), error) {
+			if tc.scopeErr != nil {
+				return nil, nil, tc.scopeErr
+			}
+			return context.WithValue(ctx, scopeKey{}, event.ID), func(err error) {
+				torndown = append(torndown, err)
+			}, nil
+		}))
+		for i := 0; i < 2; i++ {
+			client.AppendHandlerCtx("customer.*", func(ctx context.Context, event *stripe.Event) (EventResponse, error) {
+				scoped = append(scoped, ctx.Value(scopeKey{}))
+				if event.Type == "customer.deleted" {
+					return nil, errHandler
+				}
+				return nil, nil
+			})
+		}
+		client.SetDefaultFailureHandler(func(_ *stripe.Event, err error) error {
+			return err
+		})
+
+		err := client.Handle(&stripe.Event{ID: "evt_test", Type: stripe.EventType(tc.pattern)})
+		if tc.sentinel == nil && err != nil {
+			t.Fatalf("Expected %s to NOT fail, got %s", tc.name, err)
+		}
+		if tc.sentinel != nil && !errors.Is(err, tc.sentinel) {
+			t.Errorf("Expected %s to fail with %s, got %v", tc.name, tc.sentinel, err)
+		}
+
+		if tc.scopeErr != nil {
+			if len(scoped) != 0 || len(torndown) != 0 {
+				t.Errorf("Expected %s to NOT run the handlers nor the teardown, got %v %v", tc.name, scoped, torndown)
+			}
+			continue
+		}
+		if len(torndown) != 1 || !errors.Is(torndown[0], tc.sentinel) || (tc.sentinel == nil && torndown[0] != nil) {
+			t.Errorf("Expected %s to tear down once with the handler error, got %v", tc.name, torndown)
+		}
+		for _, v := range scoped {
+			if v != "evt_test" {
+				t.Errorf("Expected %s handlers to share the scoped context, got %v", tc.name, scoped)
+			}
+		}
+	}
+
+	client := NewClient(WithScopeFunc(func(ctx context.Context, _ *stripe.Event) (context.Context, func(err error), error) {
+		t.Errorf("Expected events without handlers to NOT open a scope")
+		return ctx, nil, nil
+	}))
+	client.Handle(&stripe.Event{Type: "customer.created"})
+}
+
+func TestWithScopeFuncPanic(t *testing.T) {
+	var torndown error
+	client := NewClient(WithScopeFunc(func(ctx context.Context, _ *stripe.Event) (context.Context, func(err error), error) {
+		return ctx, func(err error) {
+			torndown = err
+		}, nil
+	}))
+	client.AppendHandler("customer.created", func(_ *stripe.Event) (EventResponse, error) {
+		panic("boom")
+	})
+
+	func() {
+		defer func() {
+			if r := recover(); r != "boom" {
+				t.Errorf("Expected the handler panic to propagate, got %v", r)
+			}
+		}()
+		client.Handle(&stripe.Event{Type: "customer.created"})
+	}()
+
+	if !errors.Is(torndown, ErrHandlerPanic) {
+		t.Errorf("Expected the teardown to get ErrHandlerPanic, got %v", torndown)
+	}
+}
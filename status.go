@@ -0,0 +1,142 @@
+package stripetotrello
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	stripe "github.com/stripe/stripe-go/v76"
+)
+
+// WithStatusMapper sets how ServeHTTP turns the outcome of verifying and
+// dispatching an event into a status code, it is called with a nil error on
+// success. A mapper can fall back to DefaultStatusMapper for the errors it does
+// not care about.
+func WithStatusMapper(f func(err error) int) func(*Client) {
+	return func(c *Client) {
+		c.statusMapper = f
+	}
+}
+
+// DefaultStatusMapper answers 200 on success and for events without a handler
+// so Stripe stops retrying them, 400 for events that fail verification, 422
+// when a handler rejected the event with ErrUnprocessableEvent, 503 while the
+// client is closed or the circuit breaker is open and 500 otherwise.
+func DefaultStatusMapper(err error) int {
+	var invalid StripeInvalidEventError
+	switch {
+	case err == nil:
+		return http.StatusOK
+	case errors.As(err, &invalid):
+		return http.StatusBadRequest
+	case errors.Is(err, ErrNoHandler):
+		return http.StatusOK
+	case errors.Is(err, ErrUnprocessableEvent):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, ErrClientClosed), errors.Is(err, ErrCircuitOpen):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+type (
+	requestEventKey struct{}
+
+	// statusWriter sends status unless the responder picked one itself.
+	statusWriter struct {
+		http.ResponseWriter
+		status      int
+		wroteHeader bool
+	}
+
+	errorBody struct {
+		EventID string `json:"event_id,omitempty"`
+		Error   string `json:"error"`
+	}
+)
+
+// WithErrorResponder sets how ServeHTTP writes the body of a failed request,
+// the status from the status mapper is sent unless f calls WriteHeader. The
+// event, once verified, is available from RequestEvent(r). Successful
+// requests get an empty body. Defaults to JSONErrorResponder.
+func WithErrorResponder(f func(w http.ResponseWriter, r *http.Request, err error)) func(*Client) {
+	return func(c *Client) {
+		c.errorResponder = f
+	}
+}
+
+// JSONErrorResponder writes {"event_id": ..., "error": ...}, without the
+// event id when the request did not carry a valid event. The error is the
+// innermost one err wraps, the arguments StripeEventError reports, such as
+// the payload of an unsigned request, are not sent to the caller.
+func JSONErrorResponder(w http.ResponseWriter, r *http.Request, err error) {
+	body := errorBody{Error: errorMessage(err)}
+	if event, ok := RequestEvent(r); ok {
+		body.EventID = event.ID
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(body)
+}
+
+// errorMessage follows the Unwrap chain of err, taking the first of the
+// errors joined by a StripeEventErrors, and returns the message of its end.
+func errorMessage(err error) string {
+	for {
+		switch e := err.(type) {
+		case interface{ Unwrap() error }:
+			next := e.Unwrap()
+			if next == nil {
+				return err.Error()
+			}
+			err = next
+		case interface{ Unwrap() []error }:
+			errs := e.Unwrap()
+			if len(errs) == 0 || errs[0] == nil {
+				return err.Error()
+			}
+			err = errs[0]
+		default:
+			return err.Error()
+		}
+	}
+}
+
+// RequestEvent returns the event ServeHTTP verified for r, for error
+// responders.
+func RequestEvent(r *http.Request) (*stripe.Event, bool) {
+	event, ok := r.Context().Value(requestEventKey{}).(*stripe.Event)
+	return event, ok
+}
+
+func (st *Client) respond(w http.ResponseWriter, r *http.Request, status int, err error) {
+	if err == nil || status < http.StatusBadRequest {
+		w.WriteHeader(status)
+		return
+	}
+
+	responder := st.errorResponder
+	if responder == nil {
+		responder = JSONErrorResponder
+	}
+	sw := &statusWriter{ResponseWriter: w, status: status}
+	responder(sw, r, err)
+	if !sw.wroteHeader {
+		sw.WriteHeader(status)
+	}
+}
+
+func (sw *statusWriter) WriteHeader(status int) {
+	if sw.wroteHeader {
+		return
+	}
+	sw.wroteHeader = true
+	sw.ResponseWriter.WriteHeader(status)
+}
+
+func (sw *statusWriter) Write(b []byte) (int, error) {
+	if !sw.wroteHeader {
+		sw.WriteHeader(sw.status)
+	}
+	return sw.ResponseWriter.Write(b)
+}
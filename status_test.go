@@ -0,0 +1,147 @@
+package stripetotrello
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/skipper-digital-studio/stripetotrello/testutil"
+	stripe "github.com/stripe/stripe-go/v76"
+)
+
+func TestDefaultStatusMapper(t *testing.T) {
+	type testCase struct {
+		name   string
+		req    *http.Request
+		status int
+	}
+
+	client := NewClient(WithStripeWebhookSecret(testSecret), WithCircuitBreaker(1, time.Minute))
+	client.AppendHandler("customer.created", func(_ *stripe.Event) (EventResponse, error) {
+		return nil, nil
+	})
+	client.AppendHandler("customer.updated", func(_ *stripe.Event) (EventResponse, error) {
+		return nil, fmt.Errorf("%w: metadata.plan is missing", ErrUnprocessableEvent)
+	})
+	client.AppendHandler("customer.deleted", func(_ *stripe.Event) (EventResponse, error) {
+		return nil, errors.New("trello is down")
+	})
+
+	tcs := []testCase{
+		{"success", signedRequest(testSecret, testutil.Payload("customer.created")), http.StatusOK},
+		{"invalid signature", signedRequest("whsec_other", testutil.Payload("customer.created")), http.StatusBadRequest},
+		{"validation failure", signedRequest(testSecret, testutil.Payload("customer.updated")), http.StatusUnprocessableEntity},
+		{"unknown event type", signedRequest(testSecret, testutil.Payload("invoice.paid")), http.StatusOK},
+		{"handler failure", signedRequest(testSecret, testutil.Payload("customer.deleted")), http.StatusInternalServerError},
+		{"circuit open", signedRequest(testSecret, testutil.Payload("customer.deleted")), http.StatusServiceUnavailable},
+	}
+
+	for _, tc := range tcs {
+		rec := httptest.NewRecorder()
+		client.ServeHTTP(rec, tc.req)
+
+		if rec.Code != tc.status {
+			t.Errorf("Expected status %d for %s, got %d", tc.status, tc.name, rec.Code)
+		}
+	}
+}
+
+func TestWithStatusMapper(t *testing.T) {
+	client := NewClient(WithStripeWebhookSecret(testSecret), WithStatusMapper(func(err error) int {
+		if errors.Is(err, ErrNoHandler) {
+			return http.StatusNotFound
+		}
+		return DefaultStatusMapper(err)
+	}))
+	client.AppendHandler("customer.created", func(_ *stripe.Event) (EventResponse, error) {
+		return nil, nil
+	})
+
+	for payload, status := range map[string]int{"customer.created": http.StatusOK, "invoice.paid": http.StatusNotFound} {
+		rec := httptest.NewRecorder()
+		client.ServeHTTP(rec, signedRequest(testSecret, testutil.Payload(payload)))
+		if rec.Code != status {
+			t.Errorf("Expected status %d for %s, got %d", status, payload, rec.Code)
+		}
+	}
+}
+
+func TestJSONErrorResponder(t *testing.T) {
+	type testCase struct {
+		name    string
+		req     *http.Request
+		status  int
+		eventID string
+		err     string
+	}
+
+	client := NewClient(WithStripeWebhookSecret(testSecret))
+	client.AppendHandler("customer.created", func(_ *stripe.Event) (EventResponse, error) {
+		return nil, nil
+	})
+	client.AppendHandler("customer.deleted", func(_ *stripe.Event) (EventResponse, error) {
+		return nil, errors.New("trello is down")
+	})
+
+	tcs := []testCase{
+		{"success", signedRequest(testSecret, testutil.Payload("customer.created")), http.StatusOK, "", ""},
+		{"handler failure", signedRequest(testSecret, testutil.Payload("customer.deleted")), http.StatusInternalServerError, "evt_test", "trello is down"},
+		{"invalid signature", signedRequest("whsec_other", testutil.Payload("customer.created")), http.StatusBadRequest, "", "signature"},
+		{"unsigned", httptest.NewRequest(http.MethodPost, "/webhook", bytes.NewReader(testutil.Payload("customer.created"))), http.StatusBadRequest, "", "signature"},
+	}
+
+	for _, tc := range tcs {
+		rec := httptest.NewRecorder()
+		client.ServeHTTP(rec, tc.req)
+
+		if rec.Code != tc.status {
+			t.Errorf("Expected status %d for %s, got %d", tc.status, tc.name, rec.Code)
+		}
+		if tc.err == "" {
+			if rec.Body.Len() != 0 {
+				t.Errorf("Expected an empty body for %s, got %q", tc.name, rec.Body.String())
+			}
+			continue
+		}
+
+		var body struct {
+			EventID string `json:"event_id"`
+			Error   string `json:"error"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatalf("Expected a JSON body for %s, got %q", tc.name, rec.Body.String())
+		}
+		if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+			t.Errorf("Expected a JSON content type for %s, got %q", tc.name, ct)
+		}
+		if body.EventID != tc.eventID || !strings.Contains(body.Error, tc.err) {
+			t.Errorf("Expected event_id %q and an error with %q for %s, got %+v", tc.eventID, tc.err, tc.name, body)
+		}
+		if strings.Contains(body.Error, "with args") || strings.Contains(body.Error, "evt_test") {
+			t.Errorf("Expected %s to NOT expose the error arguments, got %q", tc.name, body.Error)
+		}
+	}
+}
+
+func TestWithErrorResponder(t *testing.T) {
+	client := NewClient(WithStripeWebhookSecret(testSecret), WithErrorResponder(func(w http.ResponseWriter, r *http.Request, err error) {
+		event, _ := RequestEvent(r)
+		w.WriteHeader(http.StatusTeapot)
+		fmt.Fprintf(w, "failed %s", event.ID)
+	}))
+	client.AppendHandler("customer.deleted", func(_ *stripe.Event) (EventResponse, error) {
+		return nil, errors.New("trello is down")
+	})
+
+	rec := httptest.NewRecorder()
+	client.ServeHTTP(rec, signedRequest(testSecret, testutil.Payload("customer.deleted")))
+	if rec.Code != http.StatusTeapot || rec.Body.String() != "failed evt_test" {
+		t.Errorf("Expected the custom response, got %d %q", rec.Code, rec.Body.String())
+	}
+}
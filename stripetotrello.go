@@ -1,25 +1,143 @@
 package stripetotrello
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net/http"
+	"reflect"
+	"runtime/debug"
+	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
+	"github.com/skipper-digital-studio/stripetotrello/internal/ratelimit"
 	stripe "github.com/stripe/stripe-go/v76"
 	"github.com/stripe/stripe-go/v76/webhook"
 )
 
+var (
+	ErrNoHandler             = errors.New("no handler registered for event type")
+	ErrSignatureVerification = errors.New("webhook signature verification failed")
+	ErrPayloadParse          = errors.New("webhook payload could not be parsed")
+	ErrHandlerPanic          = errors.New("handler panicked")
+	ErrClientClosed          = errors.New("client is closed")
+	ErrQueueFull             = errors.New("event queue is full")
+	ErrQueueStopped          = errors.New("event queue is stopped")
+	ErrNilHandler            = errors.New("handler is nil")
+	ErrMergeConflict         = errors.New("both clients have a handler for the event type")
+	ErrNoWebhookSecret       = errors.New("no webhook secret configured")
+	ErrNoStripeAPIKey        = errors.New("no stripe api key configured")
+	ErrUnsupportedObject     = errors.New("object type cannot be fetched")
+	ErrCircuitOpen           = errors.New("circuit breaker is open")
+	ErrUnknownEventType      = errors.New("unknown event type")
+	ErrUnprocessableEvent    = errors.New("event rejected by a handler")
+	ErrDuplicateHandlerName  = errors.New("handler name already registered for event type")
+)
+
 type (
-	StripeSuccessEventHandler func(event *stripe.Event, results []interface{}) error
+	EventResponse = interface{}
+
+	StripeSuccessEventHandler func(event *stripe.Event, results []EventResponse) error
 	StripeFailedEventHandler  func(event *stripe.Event, err error) error
-	StripeEventHandler        func(event *stripe.Event) (interface{}, error)
+	StripeEventHandler        func(event *stripe.Event) (EventResponse, error)
+	StripeEventHandlerCtx     func(ctx context.Context, event *stripe.Event) (EventResponse, error)
+
+	// StripeSuccessEventHandlerWithErrors receives the successful responses
+	// together with the failures. In continue-on-error mode it runs even when
+	// some handlers failed, instead of the failure handler. Otherwise it only
+	// runs once every handler succeeded, with an empty errs.
+	StripeSuccessEventHandlerWithErrors func(event *stripe.Event, responses []EventResponse, errs StripeEventErrors) (EventResponse, error)
 
+	registeredHandler struct {
+		fn       StripeEventHandlerCtx
+		pred     func(*stripe.Event) bool
+		priority int
+		// id is the code pointer of the function that was registered, before
+		// withContext wraps it.
+		id   uintptr
+		name string
+	}
+
+	// Client is safe for concurrent use, handlers can be registered and removed
+	// while events are being dispatched. A Client must not be copied by value,
+	// the copy would share its maps and locks; use Clone instead.
 	Client struct {
-		stripeWebhookSecret string
+		stripeWebhookSecrets  []string
+		tolerance             time.Duration
+		ignoreAPIVersion      bool
+		handlerTimeout        time.Duration
+		maxConcurrency        int
+		deduper               Deduper
+		recoverPanics         bool
+		livemodeOnly          bool
+		testmodeOnly          bool
+		logger                Logger
+		metrics               Metrics
+		tracer                Tracer
+		retryAttempts         int
+		retryBackoff          func(attempt int) time.Duration
+		continueOnError       bool
+		queueSize             int
+		deadLetter            DeadLetter
+		insecureSkipVerify    bool
+		verifier              Verifier
+		clock                 Clock
+		parallelCancelOnError bool
+		signatureHeader       string
+		maxBodyBytes          int64
+		secretFunc            func(r *http.Request) (string, error)
+		stripeAPIKey          string
+		stripeBackend         stripe.Backend
+		expand                map[string][]string
+		breaker               *circuitBreaker
+		beforeHandler         []BeforeHandlerHook
+		afterHandler          []AfterHandlerHook
+		strictEventTypes      bool
+		maxConcurrencyFor     map[string]int
+		rateLimits            map[string]*ratelimit.Bucket
+		dedupeHandlers        bool
+		decoders              map[string]func(raw []byte) (interface{}, error)
+		workerPool            bool
+		correlationIDFunc     func(event *stripe.Event) string
+		statusMapper          func(err error) int
+		errorResponder        func(w http.ResponseWriter, r *http.Request, err error)
+		partitionKey          func(event *stripe.Event) string
+		onDrop                func(event *stripe.Event)
+		scopeFunc             func(ctx context.Context, event *stripe.Event) (context.Context, func(err error), error)
+
+		mu                sync.RWMutex
+		handlers          map[string][]registeredHandler
+		successHandler    map[string][]StripeSuccessEventHandler
+		failureHandler    map[string]StripeFailedEventHandler
+		successWithErrors map[string]StripeSuccessEventHandlerWithErrors
+		successDetailed   map[string]StripeSuccessEventHandlerDetailed
+		failureDetailed   map[string]StripeFailedEventHandlerDetailed
+		defaultSuccess    StripeSuccessEventHandler
+		defaultFailure    StripeFailedEventHandler
+		middleware        []Middleware
 
-		handlers       map[string][]StripeEventHandler
-		successHandler map[string]StripeSuccessEventHandler
-		failureHandler map[string]StripeFailedEventHandler
+		lifecycle sync.Mutex
+		closed    bool
+		inflight  sync.WaitGroup
+		raws      sync.Map
+		results   sync.Map
+		processed atomic.Uint64
+		last      atomic.Pointer[processedEvent]
+
+		queueMu      sync.Mutex
+		queue        chan *stripe.Event
+		queueStopped bool
+		workers      sync.WaitGroup
+
+		poolOnce sync.Once
+		pool     *workerPool
+
+		// typeSems holds the WithMaxConcurrencyFor semaphores, nil for the
+		// unlimited patterns.
+		typeSems map[string]chan struct{}
 	}
 
 	StripeEventError struct {
@@ -32,6 +150,10 @@ type (
 		event string
 	}
 
+	StripeInvalidEventError struct {
+		err error
+	}
+
 	StripeEventErrors []StripeEventError
 )
 
@@ -45,20 +167,222 @@ func (s StripeUnsupportedEventError) Error() string {
 	return fmt.Sprintf("Unsupported event detected: %s", s.event)
 }
 
+func (s StripeUnsupportedEventError) Unwrap() error {
+	return ErrNoHandler
+}
+
+func NewInvalidEventError(err error) StripeInvalidEventError {
+	return StripeInvalidEventError{
+		err: err,
+	}
+}
+
+func (s StripeInvalidEventError) Error() string {
+	return fmt.Sprintf("Invalid event received: %s", s.err.Error())
+}
+
+func (s StripeInvalidEventError) Unwrap() error {
+	return s.err
+}
+
 func NewClient(cfgs ...func(*Client)) *Client {
-	c := &Client{}
+	c := &Client{
+		logger:            noopLogger{},
+		metrics:           noopMetrics{},
+		tracer:            noopTracer{},
+		clock:             realClock{},
+		signatureHeader:   SIGNATURE_HEADER,
+		maxBodyBytes:      MAX_BODY_BYTES,
+		queueSize:         QUEUE_SIZE,
+		handlers:          make(map[string][]registeredHandler),
+		successHandler:    make(map[string][]StripeSuccessEventHandler),
+		failureHandler:    make(map[string]StripeFailedEventHandler),
+		successWithErrors: make(map[string]StripeSuccessEventHandlerWithErrors),
+		successDetailed:   make(map[string]StripeSuccessEventHandlerDetailed),
+		failureDetailed:   make(map[string]StripeFailedEventHandlerDetailed),
+	}
 	for _, f := range cfgs {
 		f(c)
 	}
-	c.handlers = make(map[string][]StripeEventHandler)
-	c.successHandler = make(map[string]StripeSuccessEventHandler)
-	c.failureHandler = make(map[string]StripeFailedEventHandler)
+	c.queue = make(chan *stripe.Event, c.queueSize)
+	if c.breaker != nil {
+		c.breaker.clock = c.clock
+	}
+	for _, b := range c.rateLimits {
+		b.Now = c.clock.Now
+	}
+	c.typeSems = typeSemaphores(c.maxConcurrencyFor)
+	if c.insecureSkipVerify {
+		if len(c.stripeWebhookSecrets) > 0 {
+			c.logger.Error("insecure skip verify is ignored, a webhook secret is configured")
+		} else {
+			c.logger.Error("webhook signature verification is DISABLED, never use WithInsecureSkipVerify in production")
+		}
+	}
 	return c
 }
 
+// NewClientStrict builds the client like NewClient but fails when Validate
+// does.
+func NewClientStrict(cfgs ...func(*Client)) (*Client, error) {
+	c := NewClient(cfgs...)
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Validate fails with ErrNoWebhookSecret when the client cannot verify
+// events: no non empty webhook secret, Verifier or WithInsecureSkipVerify.
+func (st *Client) Validate() error {
+	if st.verifier != nil || st.insecureSkipVerify {
+		return nil
+	}
+	for _, secret := range st.stripeWebhookSecrets {
+		if secret != "" {
+			return nil
+		}
+	}
+	return newError("Client.Validate", nil, ErrNoWebhookSecret)
+}
+
 func WithStripeWebhookSecret(secret string) func(*Client) {
 	return func(c *Client) {
-		c.stripeWebhookSecret = secret
+		c.stripeWebhookSecrets = []string{secret}
+	}
+}
+
+// WithStripeWebhookSecrets accepts events signed with any of the given secrets,
+// which allows rolling the endpoint secret without rejecting requests.
+func WithStripeWebhookSecrets(secrets ...string) func(*Client) {
+	return func(c *Client) {
+		c.stripeWebhookSecrets = secrets
+	}
+}
+
+// WithInsecureSkipVerify makes Event decode payloads without checking their
+// signature, for local development only. A configured webhook secret takes
+// precedence, payloads are then verified and an error is logged.
+func WithInsecureSkipVerify() func(*Client) {
+	return func(c *Client) {
+		c.insecureSkipVerify = true
+	}
+}
+
+// WithTolerance overrides how old a signed payload can be, when unset the
+// stripe-go default of webhook.DefaultTolerance applies.
+func WithTolerance(d time.Duration) func(*Client) {
+	return func(c *Client) {
+		c.tolerance = d
+	}
+}
+
+// WithIgnoreAPIVersionMismatch accepts correctly signed events whose API
+// version differs from the one expected by stripe-go.
+func WithIgnoreAPIVersionMismatch() func(*Client) {
+	return func(c *Client) {
+		c.ignoreAPIVersion = true
+	}
+}
+
+// WithHandlerTimeout runs every handler invocation under a context with the
+// given deadline. Handlers should be registered with AppendHandlerCtx and
+// honour ctx.Done(), a handler that ignores the context is abandoned and its
+// late result discarded, but its goroutine keeps running until it returns.
+func WithHandlerTimeout(d time.Duration) func(*Client) {
+	return func(c *Client) {
+		c.handlerTimeout = d
+	}
+}
+
+// WithMaxConcurrency limits how many handlers HandleParallel runs at the same
+// time, zero or a negative value means unlimited.
+func WithMaxConcurrency(n int) func(*Client) {
+	return func(c *Client) {
+		c.maxConcurrency = n
+	}
+}
+
+// WithMaxConcurrencyFor overrides WithMaxConcurrency for an event type or a
+// pattern, matched like AppendHandler ones. Unlike WithMaxConcurrency the
+// limit is shared by all the events the pattern matches, a limit of 1 runs
+// one of their handlers at a time across concurrent HandleParallel calls.
+// Zero or a negative value means unlimited.
+func WithMaxConcurrencyFor(eventType string, n int) func(*Client) {
+	return func(c *Client) {
+		if c.maxConcurrencyFor == nil {
+			c.maxConcurrencyFor = make(map[string]int)
+		}
+		c.maxConcurrencyFor[eventType] = n
+	}
+}
+
+// WithPanicRecovery turns a panicking handler into an error wrapping
+// ErrHandlerPanic, with the recovered value and stack trace, which goes
+// through the failure handler like any other handler error.
+func WithPanicRecovery() func(*Client) {
+	return func(c *Client) {
+		c.recoverPanics = true
+	}
+}
+
+// WithLivemodeOnly ignores test mode events, Handle and HandleParallel return
+// nil for them without running any handler.
+func WithLivemodeOnly() func(*Client) {
+	return func(c *Client) {
+		c.livemodeOnly = true
+		c.testmodeOnly = false
+	}
+}
+
+// WithTestmodeOnly ignores live mode events, Handle and HandleParallel return
+// nil for them without running any handler.
+func WithTestmodeOnly() func(*Client) {
+	return func(c *Client) {
+		c.testmodeOnly = true
+		c.livemodeOnly = false
+	}
+}
+
+// WithHandlerRetry runs a failing handler up to maxAttempts times in total,
+// waiting backoff(attempt) between attempts. The wait is cut short when the
+// dispatch context is done, and each attempt gets its own WithHandlerTimeout.
+// Errors IsRetryable rejects, e.g. ones wrapped by MarkPermanent, are not
+// retried.
+func WithHandlerRetry(maxAttempts int, backoff func(attempt int) time.Duration) func(*Client) {
+	return func(c *Client) {
+		c.retryAttempts = maxAttempts
+		c.retryBackoff = backoff
+	}
+}
+
+// WithParallelCancelOnError makes HandleParallel cancel the context of the
+// other handlers on the first error and return it right away. Handlers still
+// running are abandoned, Close does not wait for them.
+func WithParallelCancelOnError() func(*Client) {
+	return func(c *Client) {
+		c.parallelCancelOnError = true
+	}
+}
+
+// WithDedupeHandlers makes the AppendHandler functions skip a handler whose
+// function is already registered for the same event type or pattern, compared
+// with reflect.ValueOf(fn).Pointer(). Closures created by the same function
+// literal share that pointer, so two handlers built by one factory count as
+// duplicates even when they capture different values.
+func WithDedupeHandlers() func(*Client) {
+	return func(c *Client) {
+		c.dedupeHandlers = true
+	}
+}
+
+// WithContinueOnError makes Handle run every handler even after one fails,
+// the failures are then reported together as StripeEventErrors. In this mode
+// a failure handler returning nil lets the success handlers run with the
+// responses that did succeed, for Handle and HandleParallel alike.
+func WithContinueOnError() func(*Client) {
+	return func(c *Client) {
+		c.continueOnError = true
 	}
 }
 
@@ -71,6 +395,21 @@ func (sees StripeEventErrors) Error() string {
 	return strings.Join(output, " - ")
 }
 
+func (sees StripeEventErrors) Unwrap() []error {
+	output := make([]error, len(sees))
+	for i, err := range sees {
+		output[i] = err
+	}
+	return output
+}
+
+// handlerErrors is the error Handle and HandleParallel report for failed
+// handlers, whatever the mode: a StripeEventError wrapping StripeEventErrors
+// with one entry per failed handler.
+func handlerErrors(event *stripe.Event, errs StripeEventErrors) StripeEventError {
+	return newError("Client.Handle", []interface{}{event}, errs)
+}
+
 func newError(fn string, args []interface{}, err error) StripeEventError {
 	return StripeEventError{
 		fn,
@@ -83,138 +422,969 @@ func (see StripeEventError) Error() string {
 	return fmt.Sprintf("Error calling %s - with args %v - result in error %s", see.fn, see.args, see.err.Error())
 }
 
-func (st Client) Handler(eventType string) ([]StripeEventHandler, error) {
-	handler, ok := st.handlers[eventType]
-	if !ok {
+func (see StripeEventError) Unwrap() error {
+	return see.err
+}
+
+func (st *Client) Handler(eventType string) ([]StripeEventHandler, error) {
+	handlers, err := st.handlersFor(eventType)
+	if err != nil {
+		return nil, err
+	}
+
+	output := make([]StripeEventHandler, len(handlers))
+	for i, h := range handlers {
+		output[i] = func(event *stripe.Event) (EventResponse, error) {
+			return h.fn(context.Background(), event)
+		}
+	}
+	return output, nil
+}
+
+// handlersForEvent returns the handlers to dispatch the event to, leaving out
+// the ones whose predicate rejects it and, for HandleOnly, the ones not
+// named.
+func (st *Client) handlersForEvent(ctx context.Context, event *stripe.Event) ([]StripeEventHandlerCtx, error) {
+	handlers, err := st.handlersFor(string(event.Type))
+	if err != nil {
+		return nil, err
+	}
+	if names, ok := onlyNames(ctx); ok {
+		if handlers, err = named(handlers, names); err != nil {
+			return nil, err
+		}
+	}
+
+	chain := st.middlewares()
+	output := make([]StripeEventHandlerCtx, 0, len(handlers))
+	for _, h := range handlers {
+		if h.pred == nil || h.pred(event) {
+			output = append(output, wrap(h.fn, chain))
+		}
+	}
+	if len(output) == 0 {
+		return nil, errNoneSelected
+	}
+	return output, nil
+}
+
+// errNoneSelected is returned by handlersForEvent when the AppendHandlerIf
+// predicates leave out every handler, the event is then skipped without
+// calling the success handlers nor counting it as processed.
+var errNoneSelected = errors.New("no handler selected for event")
+
+func (st *Client) handlersFor(eventType string) ([]registeredHandler, error) {
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+
+	// An event type registered with no handlers left is treated as unsupported.
+	handlers, ok := match(st.handlers, eventType)
+	if !ok || len(handlers) == 0 {
 		return nil, NewUnsupportedError(fmt.Sprintf("No %s found in available handlers", eventType))
 	}
-	return handler, nil
+	return handlers, nil
 }
 
-func (st Client) Event(raw []byte, signature string) (*stripe.Event, error) {
-	event, err := webhook.ConstructEvent(raw, signature, st.stripeWebhookSecret)
+func (st *Client) successFor(eventType string) ([]StripeSuccessEventHandler, bool) {
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+
+	h, ok := match(st.successHandler, eventType)
+	if !ok && st.defaultSuccess != nil {
+		h, ok = []StripeSuccessEventHandler{st.defaultSuccess}, true
+	}
+	if dh, found := match(st.successDetailed, eventType); found {
+		h, ok = append(h[:len(h):len(h)], st.detailedSuccess(dh)), true
+	}
+	return h, ok
+}
+
+func (st *Client) successWithErrorsFor(eventType string) (StripeSuccessEventHandlerWithErrors, bool) {
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+
+	return match(st.successWithErrors, eventType)
+}
+
+func (st *Client) failureFor(eventType string) (StripeFailedEventHandler, bool) {
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+
+	if dh, ok := match(st.failureDetailed, eventType); ok {
+		return st.detailedFailure(dh), true
+	}
+	if h, ok := match(st.failureHandler, eventType); ok {
+		return h, true
+	}
+	return st.defaultFailure, st.defaultFailure != nil
+}
+
+func (st *Client) Event(raw []byte, signature string) (*stripe.Event, error) {
+	v := st.verifier
+	if v == nil {
+		v = st.defaultVerifier(st.stripeWebhookSecrets)
+	}
+	return st.verify(v, raw, signature)
+}
+
+func (st *Client) verify(v Verifier, raw []byte, signature string) (*stripe.Event, error) {
+	event, err := v.Verify(raw, signature)
 	if err != nil {
-		return nil, newError("Client.Event", []interface{}{raw, signature}, err)
+		return nil, newError("Client.Event", []interface{}{raw, signature}, classifyEventError(err))
+	}
+	st.logger.Debug("event received", st.logFields(context.Background(), event)...)
+	return event, nil
+}
+
+func classifyEventError(err error) error {
+	if errors.Is(err, ErrSignatureVerification) || errors.Is(err, ErrPayloadParse) {
+		return err
+	}
+	switch err {
+	case webhook.ErrInvalidHeader, webhook.ErrNoValidSignature, webhook.ErrNotSigned, webhook.ErrTooOld:
+		return fmt.Errorf("%w: %w", ErrSignatureVerification, err)
+	default:
+		return fmt.Errorf("%w: %w", ErrPayloadParse, err)
 	}
+}
 
-	return &event, nil
+func IsSignatureError(err error) bool {
+	return errors.Is(err, ErrSignatureVerification)
 }
 
+// AppendHandler registers handlers for an event type or a pattern. A pattern
+// ending in "*" such as "invoice.*" matches every event type with that prefix
+// and "*" matches everything. Dispatch picks the exact event type first, then
+// the longest matching prefix and then "*", the same precedence applies to
+// success and failure handlers.
+//
+// AppendHandler silently skips nil handlers, use AppendHandlerChecked to get
+// an error instead.
 func (st *Client) AppendHandler(eventType string, handlers ...StripeEventHandler) {
+	st.logRegistration(st.appendHandlers(eventType, 0, nil, withContext(handlers)...))
+}
+
+// AppendHandlerChecked registers the handlers like AppendHandler but fails with
+// ErrNilHandler, registering none of them, when one is nil, or with
+// ErrUnknownEventType in strict mode.
+func (st *Client) AppendHandlerChecked(eventType string, handlers ...StripeEventHandler) error {
+	for i, h := range handlers {
+		if h == nil {
+			return newError("Client.AppendHandlerChecked", []interface{}{eventType, i}, ErrNilHandler)
+		}
+	}
+	return st.appendHandlers(eventType, 0, nil, withContext(handlers)...)
+}
+
+func (st *Client) AppendHandlerCtx(eventType string, handlers ...StripeEventHandlerCtx) {
+	st.logRegistration(st.appendHandlers(eventType, 0, nil, withID(handlers)...))
+}
+
+// AppendHandlerIf registers handlers that only run for events accepted by
+// pred, skipped handlers leave no entry in the success handler results.
+// When every handler of an event is skipped the event is not dispatched, the
+// success handlers are not called and it is not counted as processed.
+func (st *Client) AppendHandlerIf(eventType string, pred func(*stripe.Event) bool, handlers ...StripeEventHandler) {
+	st.logRegistration(st.appendHandlers(eventType, 0, pred, withContext(handlers)...))
+}
+
+// AppendHandlerWithPriority registers handlers that Handle runs before the
+// ones with a higher priority, handlers with the same priority keep their
+// registration order. AppendHandler uses priority 0. HandleParallel ignores
+// priorities.
+func (st *Client) AppendHandlerWithPriority(eventType string, priority int, handlers ...StripeEventHandler) {
+	st.logRegistration(st.appendHandlers(eventType, priority, nil, withContext(handlers)...))
+}
+
+// appendHandlers keeps each list sorted by priority so dispatch does not have
+// to sort.
+func (st *Client) appendHandlers(eventType string, priority int, pred func(*stripe.Event) bool, handlers ...registeredHandler) error {
+	if st.strictEventTypes && !knownEventType(eventType) {
+		return newError("Client.AppendHandler", []interface{}{eventType}, ErrUnknownEventType)
+	}
+
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
 	if st.handlers == nil {
-		st.handlers = make(map[string][]StripeEventHandler)
+		st.handlers = make(map[string][]registeredHandler)
 	}
-	h, ok := st.handlers[eventType]
-	if !ok {
-		st.handlers[eventType] = handlers
+	for _, rh := range handlers {
+		if rh.fn == nil {
+			continue
+		}
+		if rh.name != "" && nameTaken(st.handlers[eventType], rh.name) {
+			return newError("Client.AppendNamedHandler", []interface{}{eventType, rh.name}, ErrDuplicateHandlerName)
+		}
+		if st.dedupeHandlers && registered(st.handlers[eventType], rh.id) {
+			st.logger.Debug("skipping duplicate handler", "event_type", eventType)
+			continue
+		}
+		rh.pred = pred
+		rh.priority = priority
+		st.handlers[eventType] = insertHandler(st.handlers[eventType], rh)
+	}
+	return nil
+}
+
+func registered(list []registeredHandler, id uintptr) bool {
+	for _, rh := range list {
+		if rh.id == id {
+			return true
+		}
+	}
+	return false
+}
+
+func (st *Client) logRegistration(err error) {
+	if err != nil {
+		st.logger.Error("handler registration rejected", "error", err)
 	}
+}
 
-	h = append(h, handlers...)
-	st.handlers[eventType] = h
+// insertHandler returns a new list with rh inserted, dispatch reads the
+// previous one without holding the lock so it must never be written to.
+func insertHandler(list []registeredHandler, rh registeredHandler) []registeredHandler {
+	at := sort.Search(len(list), func(i int) bool { return list[i].priority > rh.priority })
+	output := make([]registeredHandler, 0, len(list)+1)
+	output = append(output, list[:at]...)
+	output = append(output, rh)
+	return append(output, list[at:]...)
 }
 
+func withContext(handlers []StripeEventHandler) []registeredHandler {
+	output := make([]registeredHandler, len(handlers))
+	for i, h := range handlers {
+		if h == nil {
+			continue
+		}
+		output[i] = registeredHandler{
+			fn: func(_ context.Context, event *stripe.Event) (EventResponse, error) {
+				return h(event)
+			},
+			id: reflect.ValueOf(h).Pointer(),
+		}
+	}
+	return output
+}
+
+func withID(handlers []StripeEventHandlerCtx) []registeredHandler {
+	output := make([]registeredHandler, len(handlers))
+	for i, h := range handlers {
+		if h == nil {
+			continue
+		}
+		output[i] = registeredHandler{fn: h, id: reflect.ValueOf(h).Pointer()}
+	}
+	return output
+}
+
+// AddSuccessHandler replaces the success handlers of the event type with
+// handler, use AppendSuccessHandler to add one to those already registered.
+// It ignores a nil handler, use RemoveSuccessHandler to drop the registered
+// ones.
 func (st *Client) AddSuccessHandler(eventType string, handler StripeSuccessEventHandler) {
-	st.successHandler[eventType] = handler
+	if handler == nil {
+		return
+	}
+
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	st.successHandler[eventType] = []StripeSuccessEventHandler{handler}
+}
+
+// AppendSuccessHandler adds success handlers that run after the ones already
+// registered for the event type, in order, with the same results. The first
+// error stops the chain and is returned.
+func (st *Client) AppendSuccessHandler(eventType string, handlers ...StripeSuccessEventHandler) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	for _, h := range handlers {
+		if h != nil {
+			st.successHandler[eventType] = append(st.successHandler[eventType], h)
+		}
+	}
+}
+
+func (st *Client) AddSuccessHandlerChecked(eventType string, handler StripeSuccessEventHandler) error {
+	if handler == nil {
+		return newError("Client.AddSuccessHandlerChecked", []interface{}{eventType}, ErrNilHandler)
+	}
+	st.AddSuccessHandler(eventType, handler)
+	return nil
+}
+
+func (st *Client) AddSuccessHandlerWithErrors(eventType string, handler StripeSuccessEventHandlerWithErrors) {
+	if handler == nil {
+		return
+	}
+
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	st.successWithErrors[eventType] = handler
 }
 
+// AddFailureHandler sets the handler called when the handlers of an event
+// fail. Whatever it returns is what Handle, HandleParallel and ServeHTTP get,
+// as is: it can wrap the error with more context, replace it, or return nil
+// to resolve the failure. AddFailureHandler ignores a nil handler, use
+// RemoveFailureHandler to drop the registered one.
 func (st *Client) AddFailureHandler(eventType string, handler StripeFailedEventHandler) {
+	if handler == nil {
+		return
+	}
+
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
 	st.failureHandler[eventType] = handler
+	delete(st.failureDetailed, eventType)
 }
 
+func (st *Client) AddFailureHandlerChecked(eventType string, handler StripeFailedEventHandler) error {
+	if handler == nil {
+		return newError("Client.AddFailureHandlerChecked", []interface{}{eventType}, ErrNilHandler)
+	}
+	st.AddFailureHandler(eventType, handler)
+	return nil
+}
+
+// RegisterHandlers appends the handlers of every event type in m, like
+// calling AppendHandler for each entry.
+func (st *Client) RegisterHandlers(m map[string][]StripeEventHandler) {
+	for eventType, handlers := range m {
+		st.AppendHandler(eventType, handlers...)
+	}
+}
+
+// RegisterSuccessHandlers sets the success handler of every event type in m,
+// replacing the registered ones.
+func (st *Client) RegisterSuccessHandlers(m map[string]StripeSuccessEventHandler) {
+	for eventType, handler := range m {
+		st.AddSuccessHandler(eventType, handler)
+	}
+}
+
+func (st *Client) RegisterFailureHandlers(m map[string]StripeFailedEventHandler) {
+	for eventType, handler := range m {
+		st.AddFailureHandler(eventType, handler)
+	}
+}
+
+// SetDefaultSuccessHandler is used for event types without a success handler
+// of their own, passing nil removes it.
+func (st *Client) SetDefaultSuccessHandler(handler StripeSuccessEventHandler) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	st.defaultSuccess = handler
+}
+
+// SetDefaultFailureHandler is used for event types without a failure handler
+// of their own, passing nil removes it.
+func (st *Client) SetDefaultFailureHandler(handler StripeFailedEventHandler) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	st.defaultFailure = handler
+}
+
+func (st *Client) RemoveHandler(eventType string) bool {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	handlers, ok := st.handlers[eventType]
+	delete(st.handlers, eventType)
+	return ok && len(handlers) > 0
+}
+
+func (st *Client) ClearHandlers() bool {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	if len(st.handlers) == 0 {
+		return false
+	}
+	st.handlers = make(map[string][]registeredHandler)
+	return true
+}
+
+func (st *Client) RemoveSuccessHandler(eventType string) bool {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	_, ok := st.successHandler[eventType]
+	_, detailed := st.successDetailed[eventType]
+	delete(st.successHandler, eventType)
+	delete(st.successDetailed, eventType)
+	return ok || detailed
+}
+
+func (st *Client) RemoveFailureHandler(eventType string) bool {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	_, ok := st.failureHandler[eventType]
+	_, detailed := st.failureDetailed[eventType]
+	delete(st.failureHandler, eventType)
+	delete(st.failureDetailed, eventType)
+	return ok || detailed
+}
+
+// Handle dispatches the event to its handlers in registration order. When
+// handlers fail and no failure handler is registered the error is always a
+// StripeEventError wrapping StripeEventErrors, one entry per failed handler,
+// the same shape HandleParallel returns. Without WithContinueOnError it stops
+// at the first failure so there is a single entry, and a failure handler is
+// given the failing handler's error itself; its result is returned instead.
 func (st *Client) Handle(event *stripe.Event) error {
-	handlers, err := st.Handler(string(event.Type))
+	return st.HandleContext(context.Background(), event)
+}
+
+func (st *Client) HandleContext(ctx context.Context, event *stripe.Event) error {
+	_, err := st.HandleCollectContext(ctx, event)
+	return err
+}
+
+func (st *Client) HandleCollect(event *stripe.Event) ([]EventResponse, error) {
+	return st.HandleCollectContext(context.Background(), event)
+}
+
+// HandleCollectContext dispatches the event like HandleContext and also
+// returns the responses produced by the handlers, in registration order.
+func (st *Client) HandleCollectContext(ctx context.Context, event *stripe.Event) ([]EventResponse, error) {
+	if err := st.begin(); err != nil {
+		return nil, newError("Client.Handle", []interface{}{event}, err)
+	}
+	defer st.inflight.Done()
+
+	return st.dispatch(ctx, event)
+}
+
+// dispatch runs the event through the handlers, the caller is responsible
+// for the in-flight tracking.
+func (st *Client) dispatch(ctx context.Context, event *stripe.Event) ([]EventResponse, error) {
+	// The breaker goes first so a short-circuited event is not recorded as seen.
+	if !st.breaker.allow(string(event.Type)) {
+		return nil, newError("Client.Handle", []interface{}{event}, ErrCircuitOpen)
+	}
+	if skip, err := st.skip(ctx, event); err != nil || skip {
+		st.breaker.release(string(event.Type))
+		return nil, err
+	}
+	ctx = st.withDecoded(withAccount(ctx, event), event)
+	ctx = st.withCorrelationID(ctx, event)
+	ctx, forget := st.withResults(ctx, event)
+	defer forget()
+
+	ctx, end := st.tracer.StartEvent(ctx, event)
+	results, err := st.handleCollect(ctx, event)
+	if err == errNoneSelected {
+		end(nil)
+		st.breaker.release(string(event.Type))
+		return nil, nil
+	}
+	end(err)
+	st.breaker.record(string(event.Type), err)
+	st.forget(ctx, event, err)
+	st.storeDeadLetter(ctx, event, err)
+	st.markProcessed(event, err)
+	return results, err
+}
+
+func (st *Client) handleCollect(ctx context.Context, event *stripe.Event) (results []EventResponse, err error) {
+	handlers, err := st.handlersForEvent(ctx, event)
+	st.checkEventType(ctx, event, err)
+	switch err.(type) {
+	case StripeUnsupportedEventError:
+		return nil, err
+	}
+	if err == errNoneSelected {
+		st.logger.Debug("no handler selected for event", st.logFields(ctx, event)...)
+		return nil, err
+	}
 	if err != nil {
-		return newError("Client.Handle", []interface{}{event}, err)
+		return nil, newError("Client.Handle", []interface{}{event}, err)
 	}
 
-	results := make([]interface{}, len(handlers))
+	st.metrics.IncEvent(string(event.Type), OUTCOME_RECEIVED)
+	st.logger.Debug("dispatching event", st.logFields(ctx, event, "handlers", len(handlers))...)
+
+	ctx, teardown, err := st.scope(ctx, event)
+	if err != nil {
+		return nil, newError("Client.Handle", []interface{}{event}, err)
+	}
+	defer func() {
+		// A panic the handlers did not recover from still rolls the scope
+		// back before it unwinds further.
+		if r := recover(); r != nil {
+			teardown(newError("Client.Handle", []interface{}{event, r}, fmt.Errorf("%w: %v", ErrHandlerPanic, r)))
+			panic(r)
+		}
+		teardown(err)
+	}()
+
+	results = make([]EventResponse, 0, len(handlers))
+	errs := StripeEventErrors{}
 	for i, h := range handlers {
-		res, err := h(event)
+		if err := ctx.Err(); err != nil {
+			return nil, handlerErrors(event, append(errs, newError(fmt.Sprintf("Client.Handle.handlers[%d]", i), []interface{}{event}, err)))
+		}
+		res, err := st.call(ctx, event, i, h)
 		if err != nil {
-			fh, ok := st.failureHandler[string(event.Type)]
+			if st.continueOnError {
+				errs = append(errs, newError(fmt.Sprintf("Client.Handle.handlers[%d]", i), []interface{}{event}, err))
+				continue
+			}
+			fh, ok := st.failureFor(string(event.Type))
 			if !ok {
-				return newError(fmt.Sprintf("Client.Handle.handlers[%d]", i), []interface{}{event}, err)
+				return nil, handlerErrors(event, StripeEventErrors{newError(fmt.Sprintf("Client.Handle.handlers[%d]", i), []interface{}{event}, err)})
 			}
-			return fh(event, err)
+			// The failure handler keeps getting the handler's own error here.
+			return nil, fh(event, err)
 		}
-		results[i] = res
+		results = append(results, res)
 	}
 
-	h, ok := st.successHandler[string(event.Type)]
-	if !ok {
-		return nil
+	return results, st.finish(ctx, event, results, errs)
+}
+
+// finish runs the success and failure handlers once every handler returned.
+// errs is only non empty in continue-on-error mode or for HandleParallel.
+func (st *Client) finish(ctx context.Context, event *stripe.Event, results []EventResponse, errs StripeEventErrors) error {
+	eventType := string(event.Type)
+
+	if len(errs) > 0 {
+		if wh, ok := st.successWithErrorsFor(eventType); ok && st.continueOnError {
+			res, err := wh(event, results, errs)
+			setFinalResponse(ctx, res)
+			return err
+		}
+
+		nErr := handlerErrors(event, errs)
+		fh, ok := st.failureFor(eventType)
+		if !ok {
+			return nErr
+		}
+		// A failure handler returning nil resolves the failures, in
+		// continue-on-error mode the success handlers then get the results that
+		// did succeed.
+		if err := fh(event, nErr); err != nil || !st.continueOnError {
+			return err
+		}
+	}
+
+	st.logger.Info("event handled", st.logFields(ctx, event)...)
+
+	handlers, _ := st.successFor(eventType)
+	for _, sh := range handlers {
+		if err := sh(event, results); err != nil {
+			return err
+		}
 	}
 
-	if err = h(event, results); err != nil {
+	if wh, ok := st.successWithErrorsFor(eventType); ok {
+		res, err := wh(event, results, nil)
+		setFinalResponse(ctx, res)
 		return err
 	}
 	return nil
 }
 
+// skip reports whether the event should not be dispatched, either because of
+// its mode or because it was already seen. Replays are not deduplicated.
+func (st *Client) skip(ctx context.Context, event *stripe.Event) (bool, error) {
+	if (st.livemodeOnly && !event.Livemode) || (st.testmodeOnly && event.Livemode) {
+		return true, nil
+	}
+	if isReplay(ctx) {
+		return false, nil
+	}
+	seen, err := st.seen(event)
+	if seen {
+		st.metrics.IncEvent(string(event.Type), OUTCOME_DEDUPED)
+		st.logger.Debug("skipping duplicate event", st.logFields(ctx, event)...)
+		recordDeduped(ctx)
+	}
+	return seen, err
+}
+
+func (st *Client) call(ctx context.Context, event *stripe.Event, i int, h StripeEventHandlerCtx) (EventResponse, error) {
+	ctx, end := st.tracer.StartHandler(ctx, event, i)
+	for _, hook := range st.beforeHandler {
+		hook(ctx, event, i)
+	}
+	start := time.Now()
+	res, err := st.callWithRetry(ctx, event, h)
+	elapsed := time.Since(start)
+	for _, hook := range st.afterHandler {
+		hook(ctx, event, i, res, err)
+	}
+	st.metrics.ObserveDuration(string(event.Type), elapsed)
+	recordResult(ctx, i, Result{Response: res, Duration: elapsed, Err: err})
+	recordOutcome(ctx, err)
+	end(err)
+	if err != nil {
+		st.metrics.IncEvent(string(event.Type), OUTCOME_FAILURE)
+		st.logger.Error("handler failed", st.logFields(ctx, event, "handler", i, "error", err)...)
+		return res, err
+	}
+	st.metrics.IncEvent(string(event.Type), OUTCOME_SUCCESS)
+	return res, nil
+}
+
+func (st *Client) callWithRetry(ctx context.Context, event *stripe.Event, h StripeEventHandlerCtx) (EventResponse, error) {
+	if st.retryAttempts <= 1 {
+		return st.attempt(ctx, event, h)
+	}
+
+	for attempt := 1; ; attempt++ {
+		res, err := st.attempt(ctx, event, h)
+		if err == nil {
+			return res, nil
+		}
+		if attempt >= st.retryAttempts || !IsRetryable(err) {
+			return nil, newError("Client.callWithRetry", []interface{}{event, attempt}, err)
+		}
+
+		var delay time.Duration
+		if st.retryBackoff != nil {
+			delay = st.retryBackoff(attempt)
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, newError("Client.callWithRetry", []interface{}{event, attempt}, ctx.Err())
+		}
+	}
+}
+
+// attempt runs the handler once, every attempt of a retried handler takes
+// its own WithRateLimit token.
+func (st *Client) attempt(ctx context.Context, event *stripe.Event, h StripeEventHandlerCtx) (EventResponse, error) {
+	if bucket, ok := match(st.rateLimits, string(event.Type)); ok {
+		if err := bucket.Wait(ctx); err != nil {
+			return nil, newError("Client.attempt", []interface{}{event}, err)
+		}
+	}
+	return st.callWithTimeout(ctx, event, h)
+}
+
+func (st *Client) callWithTimeout(ctx context.Context, event *stripe.Event, h StripeEventHandlerCtx) (EventResponse, error) {
+	if st.handlerTimeout <= 0 {
+		return st.invoke(ctx, event, h)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, st.handlerTimeout)
+	defer cancel()
+
+	type result struct {
+		res EventResponse
+		err error
+	}
+	done := make(chan result, 1)
+	go func() {
+		res, err := st.invoke(ctx, event, h)
+		done <- result{res, err}
+	}()
+
+	select {
+	case r := <-done:
+		return r.res, r.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (st *Client) invoke(ctx context.Context, event *stripe.Event, h StripeEventHandlerCtx) (res EventResponse, err error) {
+	if st.recoverPanics {
+		defer func() {
+			if r := recover(); r != nil {
+				res = nil
+				err = newError("Client.invoke", []interface{}{event, r}, fmt.Errorf("%w: %v\n%s", ErrHandlerPanic, r, debug.Stack()))
+			}
+		}()
+	}
+	return h(ctx, event)
+}
+
+// handleSingle runs the only handler of the event on the calling goroutine,
+// with the outcome handleParallel would give it.
+func (st *Client) handleSingle(ctx context.Context, event *stripe.Event, h StripeEventHandlerCtx) error {
+	sem, _ := match(st.typeSems, string(event.Type))
+	if err := acquire(ctx, sem); err != nil {
+		return st.finish(ctx, event, []EventResponse{}, StripeEventErrors{newError("Client.Handle.handlers[0]", []interface{}{event}, err)})
+	}
+	res, err := st.call(ctx, event, 0, h)
+	release(sem)
+	if err == nil {
+		return st.finish(ctx, event, []EventResponse{res}, nil)
+	}
+
+	fErr := newError("Client.Handle.handlers[0]", []interface{}{event}, err)
+	if !st.parallelCancelOnError {
+		return st.finish(ctx, event, []EventResponse{}, StripeEventErrors{fErr})
+	}
+	nErr := handlerErrors(event, StripeEventErrors{fErr})
+	fh, ok := st.failureFor(string(event.Type))
+	if !ok {
+		return nErr
+	}
+	return fh(event, nErr)
+}
+
+// checkAccounting reports handlers that neither succeeded nor failed, which
+// would be a bug in the dispatch code rather than in the handlers.
+func checkAccounting(handlers, succeeded, errored int) error {
+	if missing := handlers - succeeded - errored; missing != 0 {
+		return fmt.Errorf("%d of %d handlers returned neither a response nor an error: %d succeeded, %d errored", missing, handlers, succeeded, errored)
+	}
+	return nil
+}
+
+// HandleParallel dispatches the event to all its handlers concurrently.
+// Handler failures are reported like Handle does, as a StripeEventError
+// wrapping StripeEventErrors.
 func (st *Client) HandleParallel(event *stripe.Event) error {
-	handlers, err := st.Handler(string(event.Type))
+	return st.HandleParallelContext(context.Background(), event)
+}
+
+func (st *Client) HandleParallelContext(ctx context.Context, event *stripe.Event) error {
+	if err := st.begin(); err != nil {
+		return newError("Client.HandleParallel", []interface{}{event}, err)
+	}
+	defer st.inflight.Done()
+
+	return st.dispatchParallel(ctx, event)
+}
+
+func (st *Client) dispatchParallel(ctx context.Context, event *stripe.Event) error {
+	if !st.breaker.allow(string(event.Type)) {
+		return newError("Client.HandleParallel", []interface{}{event}, ErrCircuitOpen)
+	}
+	if skip, err := st.skip(ctx, event); err != nil || skip {
+		st.breaker.release(string(event.Type))
+		return err
+	}
+	ctx = st.withDecoded(withAccount(ctx, event), event)
+	ctx = st.withCorrelationID(ctx, event)
+	ctx, forget := st.withResults(ctx, event)
+	defer forget()
+
+	ctx, end := st.tracer.StartEvent(ctx, event)
+	err := st.handleParallel(ctx, event)
+	if err == errNoneSelected {
+		end(nil)
+		st.breaker.release(string(event.Type))
+		return nil
+	}
+	end(err)
+	st.breaker.record(string(event.Type), err)
+	st.forget(ctx, event, err)
+	st.storeDeadLetter(ctx, event, err)
+	st.markProcessed(event, err)
+	return err
+}
+
+func (st *Client) handleParallel(ctx context.Context, event *stripe.Event) error {
+	handlers, err := st.handlersForEvent(ctx, event)
+	st.checkEventType(ctx, event, err)
 	switch err.(type) {
 	case StripeEventError:
 		return newError("Client.HandleParallel", []interface{}{event}, err)
 	case StripeUnsupportedEventError:
 		return err
 	}
+	if err == errNoneSelected {
+		st.logger.Debug("no handler selected for event", st.logFields(ctx, event)...)
+		return err
+	}
 	if err != nil {
 		return newError("Client.HandleParallel", []interface{}{event}, err)
 	}
+	st.metrics.IncEvent(string(event.Type), OUTCOME_RECEIVED)
+	st.logger.Debug("dispatching event in parallel", st.logFields(ctx, event, "handlers", len(handlers))...)
+
+	// Without a second handler there is nothing to run concurrently.
+	if len(handlers) == 1 {
+		return st.handleSingle(ctx, event, handlers[0])
+	}
+
 	var wg sync.WaitGroup
 
-	errors := make(chan StripeEventError, len(handlers))
-	results := make(chan interface{}, len(handlers))
+	// In cancel-on-error mode the first handler error cancels ctx and is
+	// returned without waiting for the other handlers.
+	abort := func(StripeEventError) {}
+	var aborted chan struct{}
+	var first StripeEventError
+	if st.parallelCancelOnError {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithCancel(ctx)
+		defer cancel()
+
+		var once sync.Once
+		aborted = make(chan struct{})
+		abort = func(err StripeEventError) {
+			once.Do(func() {
+				first = err
+				cancel()
+				close(aborted)
+			})
+		}
+	}
+
+	failures := make(chan StripeEventError, len(handlers))
+	completed := make(chan int, len(handlers))
+	results := make([]EventResponse, len(handlers))
+
+	sem, ok := match(st.typeSems, string(event.Type))
+	if !ok && st.maxConcurrency > 0 {
+		sem = make(chan struct{}, st.maxConcurrency)
+	}
 
 	for i, h := range handlers {
+		// A cancelled context stops scheduling, the remaining handlers are
+		// reported as failed.
+		if err := acquire(ctx, sem); err != nil {
+			failures <- newError(fmt.Sprintf("Client.Handle.handlers[%d]", i), []interface{}{event}, err)
+			continue
+		}
 		wg.Add(1)
-		go func() {
+		st.spawn(func() {
 			defer wg.Done()
-			res, err := h(event)
+			defer release(sem)
+			res, err := st.call(ctx, event, i, h)
 			if err != nil {
-				errors <- newError(fmt.Sprintf("Client.Handle.handlers[%d]", i), []interface{}{event}, err)
+				fErr := newError(fmt.Sprintf("Client.Handle.handlers[%d]", i), []interface{}{event}, err)
+				failures <- fErr
+				abort(fErr)
+				return
 			}
-			results <- res
+			results[i] = res
+			completed <- i
+		})
+	}
+
+	if aborted != nil {
+		done := make(chan struct{})
+		go func() {
+			wg.Wait()
+			close(done)
 		}()
+
+		select {
+		case <-aborted:
+			nErr := handlerErrors(event, StripeEventErrors{first})
+			fh, ok := st.failureFor(string(event.Type))
+			if !ok {
+				return nErr
+			}
+			return fh(event, nErr)
+		case <-done:
+		}
 	}
 
 	wg.Wait()
-	close(errors)
-	close(results)
+	close(failures)
+	close(completed)
 
-	if len(errors) > 0 {
-		errs := StripeEventErrors{}
-		for err := range errors {
-			errs = append(errs, err)
-		}
-		nErr := newError("Client.Handle", []interface{}{event}, errs)
-		fh, ok := st.failureHandler[string(event.Type)]
+	if err := checkAccounting(len(handlers), len(completed), len(failures)); err != nil {
+		nErr := newError("Client.HandleParallel", []interface{}{event}, err)
+		fh, ok := st.failureFor(string(event.Type))
 		if !ok {
 			return nErr
 		}
-		tt := fh(event, nErr)
-		return tt
+		return fh(event, nErr)
 	}
 
-	if len(results) != len(handlers) {
-		nErr := newError("Client.HandleParallel", []interface{}{event}, fmt.Errorf("Not all the handlers return a valid response"))
-		fh, ok := st.failureHandler[string(event.Type)]
-		if !ok {
-			return nErr
+	if len(failures) == 0 {
+		return st.finish(ctx, event, results, nil)
+	}
+
+	// The pooled buffer goes back once the failure handler ran, it only ever
+	// sees the detached copy.
+	buf := getErrs()
+	defer putErrs(buf)
+	for err := range failures {
+		*buf = append(*buf, err)
+	}
+	errs := detach(*buf)
+	succeeded := make([]bool, len(handlers))
+	for i := range completed {
+		succeeded[i] = true
+	}
+	partial := make([]EventResponse, 0, len(completed))
+	for i, res := range results {
+		if succeeded[i] {
+			partial = append(partial, res)
+		}
+	}
+	return st.finish(ctx, event, partial, errs)
+}
+
+func typeSemaphores(limits map[string]int) map[string]chan struct{} {
+	sems := make(map[string]chan struct{}, len(limits))
+	for eventType, n := range limits {
+		if n > 0 {
+			sems[eventType] = make(chan struct{}, n)
+		} else {
+			sems[eventType] = nil
 		}
-		return fh(event, nErr)
 	}
+	return sems
+}
 
-	rs := []interface{}{}
-	for r := range results {
-		rs = append(rs, r)
+func acquire(ctx context.Context, sem chan struct{}) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if sem == nil {
+		return nil
 	}
 
-	sh, ok := st.successHandler[string(event.Type)]
-	if !ok {
+	select {
+	case sem <- struct{}{}:
 		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func release(sem chan struct{}) {
+	if sem != nil {
+		<-sem
+	}
+}
+
+const WILDCARD = "*"
+
+func match[T any](m map[string]T, eventType string) (T, bool) {
+	if v, ok := m[eventType]; ok {
+		return v, true
+	}
+
+	var (
+		best    T
+		bestLen = -1
+	)
+	for pattern, v := range m {
+		if pattern == WILDCARD || !strings.HasSuffix(pattern, WILDCARD) {
+			continue
+		}
+		prefix := strings.TrimSuffix(pattern, WILDCARD)
+		if strings.HasPrefix(eventType, prefix) && len(prefix) > bestLen {
+			best, bestLen = v, len(prefix)
+		}
+	}
+	if bestLen >= 0 {
+		return best, true
 	}
 
-	return sh(event, rs)
+	v, ok := m[WILDCARD]
+	return v, ok
 }
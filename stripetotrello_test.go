@@ -1,8 +1,15 @@
 package stripetotrello
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	stripe "github.com/stripe/stripe-go/v76"
 )
@@ -463,3 +470,1582 @@ func TestHandleWithErrorAndSuccessHandler(t *testing.T) {
 		}
 	}
 }
+
+func TestNewClientRegistration(t *testing.T) {
+	client := NewClient(WithStripeWebhookSecret("whsec_test"))
+
+	client.AppendHandler("customer.created", func(_ *stripe.Event) (interface{}, error) {
+		return nil, nil
+	})
+	client.AddSuccessHandler("customer.created", func(_ *stripe.Event, _ []interface{}) error {
+		return nil
+	})
+	client.AddFailureHandler("customer.created", func(_ *stripe.Event, err error) error {
+		return err
+	})
+
+	if err := client.Handle(&stripe.Event{Type: "customer.created"}); err != nil {
+		t.Errorf("Event should have NOT failed event type = customer.created, got %s", err)
+	}
+}
+
+func TestAppendHandler(t *testing.T) {
+	type testCase struct {
+		event    string
+		handlers int
+		lenght   int
+	}
+
+	noop := func(_ *stripe.Event) (interface{}, error) {
+		return nil, nil
+	}
+
+	tcs := []testCase{
+		{"customer.created", 1, 1},
+		{"customer.created", 1, 2},
+		{"customer.created", 3, 5},
+		{"customer.updated", 3, 3},
+		{"customer.updated", 0, 3},
+		{"customer.deleted", 2, 2},
+	}
+
+	client := NewClient()
+	for _, tc := range tcs {
+		handlers := make([]StripeEventHandler, tc.handlers)
+		for i := range handlers {
+			handlers[i] = noop
+		}
+		client.AppendHandler(tc.event, handlers...)
+
+		res, err := client.Handler(tc.event)
+		if err != nil {
+			t.Errorf("Expected handlers for event type = %s, got error %s", tc.event, err)
+			continue
+		}
+		if len(res) != tc.lenght {
+			t.Errorf("Expected number of handlers %d for event type = %s, got %d", tc.lenght, tc.event, len(res))
+		}
+	}
+}
+
+func TestHandleParallelRunsEachHandler(t *testing.T) {
+	var mu sync.Mutex
+	ran := map[string]int{}
+
+	record := func(name string) StripeEventHandler {
+		return func(_ *stripe.Event) (interface{}, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			ran[name]++
+			return name, nil
+		}
+	}
+
+	client := NewClient()
+	client.AppendHandler("customer.created", record("first"), record("second"), record("third"))
+
+	if err := client.HandleParallel(&stripe.Event{Type: "customer.created"}); err != nil {
+		t.Fatalf("Event should have NOT failed event type = customer.created, got %s", err)
+	}
+
+	for _, name := range []string{"first", "second", "third"} {
+		if ran[name] != 1 {
+			t.Errorf("Expected handler %s to run once, ran %d times", name, ran[name])
+		}
+	}
+}
+
+func TestHandleParallelSkipsFailedResults(t *testing.T) {
+	client := NewClient()
+	client.AppendHandler("customer.created", func(_ *stripe.Event) (interface{}, error) {
+		return "testing 1", nil
+	}, func(_ *stripe.Event) (interface{}, error) {
+		return nil, newError("It fails", []interface{}{"error"}, fmt.Errorf("test"))
+	}, func(_ *stripe.Event) (interface{}, error) {
+		return "testing 3", nil
+	})
+
+	successCalled := false
+	client.AddSuccessHandler("customer.created", func(_ *stripe.Event, results []interface{}) error {
+		successCalled = true
+		for i, r := range results {
+			if r == nil {
+				t.Errorf("Expected no nil result, got nil at index %d", i)
+			}
+		}
+		return nil
+	})
+
+	if err := client.HandleParallel(&stripe.Event{Type: "customer.created"}); err == nil {
+		t.Errorf("Event should have failed event type = customer.created")
+	}
+
+	if successCalled {
+		t.Errorf("Success handler should NOT have been called when a handler fails")
+	}
+}
+
+func TestHandleParallelPreservesOrder(t *testing.T) {
+	sleeper := func(d time.Duration, res string) StripeEventHandler {
+		return func(_ *stripe.Event) (EventResponse, error) {
+			time.Sleep(d)
+			return res, nil
+		}
+	}
+
+	client := NewClient()
+	client.AppendHandler("customer.created",
+		sleeper(30*time.Millisecond, "first"),
+		sleeper(0, "second"),
+		sleeper(15*time.Millisecond, "third"),
+	)
+
+	expected := []string{"first", "second", "third"}
+	client.AddSuccessHandler("customer.created", func(_ *stripe.Event, results []EventResponse) error {
+		if len(results) != len(expected) {
+			return fmt.Errorf("Expected %d results, got %d", len(expected), len(results))
+		}
+		for i, r := range results {
+			if r != expected[i] {
+				return fmt.Errorf("Expected result %s at index %d, got %v", expected[i], i, r)
+			}
+		}
+		return nil
+	})
+
+	for i := 0; i < 5; i++ {
+		if err := client.HandleParallel(&stripe.Event{Type: "customer.created"}); err != nil {
+			t.Errorf("Event should have NOT failed event type = customer.created, got %s", err)
+		}
+	}
+}
+
+func TestHandleContext(t *testing.T) {
+	type ctxKey struct{}
+
+	client := NewClient()
+	client.AppendHandlerCtx("customer.created", func(ctx context.Context, _ *stripe.Event) (EventResponse, error) {
+		v, _ := ctx.Value(ctxKey{}).(string)
+		if v != "request-scoped" {
+			return nil, fmt.Errorf("Expected context value request-scoped, got %q", v)
+		}
+		return v, nil
+	})
+
+	ctx := context.WithValue(context.Background(), ctxKey{}, "request-scoped")
+	if err := client.HandleContext(ctx, &stripe.Event{Type: "customer.created"}); err != nil {
+		t.Errorf("Event should have NOT failed event type = customer.created, got %s", err)
+	}
+	if err := client.HandleParallelContext(ctx, &stripe.Event{Type: "customer.created"}); err != nil {
+		t.Errorf("Event should have NOT failed event type = customer.created, got %s", err)
+	}
+}
+
+func TestHandleParallelContextCancelled(t *testing.T) {
+	ran := false
+	client := NewClient()
+	client.AppendHandlerCtx("customer.created", func(_ context.Context, _ *stripe.Event) (EventResponse, error) {
+		ran = true
+		return nil, nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := client.HandleParallelContext(ctx, &stripe.Event{Type: "customer.created"})
+	if err == nil {
+		t.Errorf("Event should have failed with a cancelled context")
+	}
+	if ran {
+		t.Errorf("Handler should NOT have been scheduled with a cancelled context")
+	}
+}
+
+func TestHandlerTimeout(t *testing.T) {
+	type testCase struct {
+		event      stripe.Event
+		shouldFail bool
+	}
+
+	client := NewClient(WithHandlerTimeout(20 * time.Millisecond))
+	client.AppendHandlerCtx("customer.created", func(ctx context.Context, _ *stripe.Event) (EventResponse, error) {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(time.Second):
+			return "late", nil
+		}
+	})
+	client.AppendHandler("customer.updated", func(_ *stripe.Event) (EventResponse, error) {
+		time.Sleep(200 * time.Millisecond)
+		return "ignores context", nil
+	})
+	client.AppendHandler("customer.deleted", func(_ *stripe.Event) (EventResponse, error) {
+		return "fast", nil
+	})
+
+	tcs := []testCase{
+		{stripe.Event{Type: "customer.created"}, true},
+		{stripe.Event{Type: "customer.updated"}, true},
+		{stripe.Event{Type: "customer.deleted"}, false},
+	}
+
+	for _, tc := range tcs {
+		for _, handle := range []func(*stripe.Event) error{client.Handle, client.HandleParallel} {
+			start := time.Now()
+			err := handle(&tc.event)
+			if err != nil && !tc.shouldFail {
+				t.Errorf("Event should have NOT failed event type = %s", tc.event.Type)
+			}
+
+			if err == nil && tc.shouldFail {
+				t.Errorf("Event should have failed event type = %s", tc.event.Type)
+			}
+
+			if err != nil && !strings.Contains(err.Error(), context.DeadlineExceeded.Error()) {
+				t.Errorf("Expected a deadline exceeded error, got %s", err)
+			}
+
+			if elapsed := time.Since(start); elapsed > 150*time.Millisecond {
+				t.Errorf("Expected the handler to be abandoned after the timeout, took %s", elapsed)
+			}
+		}
+	}
+}
+
+func TestHandleParallelMaxConcurrency(t *testing.T) {
+	type testCase struct {
+		limit    int
+		handlers int
+	}
+
+	tcs := []testCase{
+		{1, 5},
+		{2, 6},
+		{3, 10},
+	}
+
+	for _, tc := range tcs {
+		var running, peak, calls int32
+		handler := func(_ *stripe.Event) (EventResponse, error) {
+			n := atomic.AddInt32(&running, 1)
+			for {
+				p := atomic.LoadInt32(&peak)
+				if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			atomic.AddInt32(&running, -1)
+			atomic.AddInt32(&calls, 1)
+			return "done", nil
+		}
+
+		client := NewClient(WithMaxConcurrency(tc.limit))
+		for i := 0; i < tc.handlers; i++ {
+			client.AppendHandler("customer.created", handler)
+		}
+
+		if err := client.HandleParallel(&stripe.Event{Type: "customer.created"}); err != nil {
+			t.Errorf("Event should have NOT failed event type = customer.created, got %s", err)
+		}
+		if p := atomic.LoadInt32(&peak); p > int32(tc.limit) {
+			t.Errorf("Expected at most %d handlers running simultaneously, got %d", tc.limit, p)
+		}
+		if c := atomic.LoadInt32(&calls); c != int32(tc.handlers) {
+			t.Errorf("Expected %d handlers to run, got %d", tc.handlers, c)
+		}
+	}
+}
+
+func TestRemoveHandlers(t *testing.T) {
+	noop := func(_ *stripe.Event) (EventResponse, error) {
+		return nil, nil
+	}
+
+	client := NewClient()
+	client.AppendHandler("customer.created", noop, noop)
+	client.AppendHandler("customer.updated", noop)
+	client.AddSuccessHandler("customer.created", func(_ *stripe.Event, _ []EventResponse) error {
+		return nil
+	})
+	client.AddFailureHandler("customer.created", func(_ *stripe.Event, err error) error {
+		return err
+	})
+
+	if !client.RemoveHandler("customer.created") {
+		t.Errorf("Expected RemoveHandler to remove event type = customer.created")
+	}
+	if client.RemoveHandler("customer.created") {
+		t.Errorf("Expected RemoveHandler to report nothing removed for event type = customer.created")
+	}
+	if _, err := client.Handler("customer.created"); err == nil {
+		t.Errorf("Expected no handlers for event type = customer.created")
+	}
+
+	if !client.RemoveSuccessHandler("customer.created") || client.RemoveSuccessHandler("customer.created") {
+		t.Errorf("Expected RemoveSuccessHandler to remove the success handler exactly once")
+	}
+	if !client.RemoveFailureHandler("customer.created") || client.RemoveFailureHandler("customer.created") {
+		t.Errorf("Expected RemoveFailureHandler to remove the failure handler exactly once")
+	}
+
+	if !client.ClearHandlers() {
+		t.Errorf("Expected ClearHandlers to remove event type = customer.updated")
+	}
+	if client.ClearHandlers() {
+		t.Errorf("Expected ClearHandlers to report nothing removed on an empty client")
+	}
+	if _, err := client.Handler("customer.updated"); err == nil {
+		t.Errorf("Expected no handlers for event type = customer.updated")
+	}
+}
+
+func TestConcurrentRegistrationAndDispatch(t *testing.T) {
+	client := NewClient()
+	client.AppendHandler("customer.created", func(_ *stripe.Event) (EventResponse, error) {
+		return "testing", nil
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(3)
+		go func() {
+			defer wg.Done()
+			client.AppendHandler("customer.created", func(_ *stripe.Event) (EventResponse, error) {
+				return "testing", nil
+			})
+			client.AddSuccessHandler("customer.created", func(_ *stripe.Event, _ []EventResponse) error {
+				return nil
+			})
+			client.AddFailureHandler("customer.created", func(_ *stripe.Event, err error) error {
+				return err
+			})
+		}()
+		go func() {
+			defer wg.Done()
+			if err := client.Handle(&stripe.Event{Type: "customer.created"}); err != nil {
+				t.Errorf("Event should have NOT failed event type = customer.created, got %s", err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			if err := client.HandleParallel(&stripe.Event{Type: "customer.created"}); err != nil {
+				t.Errorf("Event should have NOT failed event type = customer.created, got %s", err)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// Inserting before the registered handlers must not write to the list a
+// dispatch is reading, run with -race.
+func TestConcurrentPriorityRegistrationAndDispatch(t *testing.T) {
+	noop := func(_ *stripe.Event) (EventResponse, error) {
+		return "testing", nil
+	}
+
+	client := NewClient()
+	client.AppendHandler("customer.created", noop, noop, noop)
+
+	var wg sync.WaitGroup
+	var started sync.WaitGroup
+	done := make(chan struct{})
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		started.Add(1)
+		go func() {
+			defer wg.Done()
+			started.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				if err := client.Handle(&stripe.Event{Type: "customer.created"}); err != nil {
+					t.Errorf("Event should have NOT failed event type = customer.created, got %s", err)
+				}
+			}
+		}()
+	}
+
+	started.Wait()
+	for i := 0; i < 500; i++ {
+		client.AppendHandlerWithPriority("customer.created", -i, noop)
+		other := NewClient()
+		other.AppendHandlerWithPriority("customer.created", -i, noop)
+		if err := client.Merge(other); err != nil {
+			t.Errorf("Merge should have NOT failed, got %s", err)
+		}
+	}
+	close(done)
+	wg.Wait()
+
+	if count := client.HandlerCount("customer.created"); count != 1003 {
+		t.Errorf("Expected 1003 handlers, got %d", count)
+	}
+}
+
+func TestHandleCollect(t *testing.T) {
+	type testCase struct {
+		event      stripe.Event
+		results    []EventResponse
+		shouldFail bool
+	}
+
+	client := NewClient()
+	client.AppendHandler("customer.created", func(_ *stripe.Event) (EventResponse, error) {
+		return "card_1", nil
+	}, func(_ *stripe.Event) (EventResponse, error) {
+		return "card_2", nil
+	})
+	client.AppendHandler("customer.deleted", func(_ *stripe.Event) (EventResponse, error) {
+		return nil, newError("It fails", []interface{}{"error"}, fmt.Errorf("test"))
+	})
+
+	tcs := []testCase{
+		{stripe.Event{Type: "customer.created"}, []EventResponse{"card_1", "card_2"}, false},
+		{stripe.Event{Type: "customer.deleted"}, nil, true},
+		{stripe.Event{Type: "customer.updated"}, nil, true},
+	}
+
+	for _, tc := range tcs {
+		res, err := client.HandleCollect(&tc.event)
+		if err != nil && !tc.shouldFail {
+			t.Errorf("Event should have NOT failed event type = %s", tc.event.Type)
+		}
+
+		if err == nil && tc.shouldFail {
+			t.Errorf("Event should have failed event type = %s", tc.event.Type)
+		}
+
+		if len(res) != len(tc.results) {
+			t.Errorf("Expected number of results %d, got %d", len(tc.results), len(res))
+			continue
+		}
+		for i := range res {
+			if res[i] != tc.results[i] {
+				t.Errorf("Expected result %v at index %d, got %v", tc.results[i], i, res[i])
+			}
+		}
+	}
+}
+
+func TestErrNoHandler(t *testing.T) {
+	client := NewClient()
+	client.AppendHandler("customer.created", func(_ *stripe.Event) (EventResponse, error) {
+		return nil, nil
+	})
+
+	if _, err := client.Handler("customer.updated"); !errors.Is(err, ErrNoHandler) {
+		t.Errorf("Expected Handler to return ErrNoHandler, got %v", err)
+	}
+	if err := client.Handle(&stripe.Event{Type: "customer.updated"}); !errors.Is(err, ErrNoHandler) {
+		t.Errorf("Expected Handle to return ErrNoHandler, got %v", err)
+	}
+	if err := client.HandleParallel(&stripe.Event{Type: "customer.updated"}); !errors.Is(err, ErrNoHandler) {
+		t.Errorf("Expected HandleParallel to return ErrNoHandler, got %v", err)
+	}
+	if err := client.Handle(&stripe.Event{Type: "customer.created"}); errors.Is(err, ErrNoHandler) {
+		t.Errorf("Expected Handle to NOT return ErrNoHandler for a registered event type")
+	}
+}
+
+func TestStripeEventErrorUnwrap(t *testing.T) {
+	cause := fmt.Errorf("trello unavailable")
+
+	single := newError("Client.Handle", []interface{}{"event"}, cause)
+	if !errors.Is(single, cause) {
+		t.Errorf("Expected errors.Is to reach the cause through StripeEventError")
+	}
+
+	multi := newError("Client.Handle", []interface{}{"event"}, StripeEventErrors{
+		newError("Client.Handle.handlers[0]", nil, fmt.Errorf("other")),
+		newError("Client.Handle.handlers[1]", nil, cause),
+	})
+	if !errors.Is(multi, cause) {
+		t.Errorf("Expected errors.Is to reach the cause through StripeEventErrors")
+	}
+
+	var target StripeEventErrors
+	if !errors.As(multi, &target) || len(target) != 2 {
+		t.Errorf("Expected errors.As to find the StripeEventErrors with 2 errors, got %v", target)
+	}
+
+	client := NewClient()
+	client.AppendHandler("customer.created", func(_ *stripe.Event) (EventResponse, error) {
+		return nil, cause
+	})
+	if err := client.Handle(&stripe.Event{Type: "customer.created"}); !errors.Is(err, cause) {
+		t.Errorf("Expected Handle error to wrap the handler error, got %v", err)
+	}
+	if err := client.HandleParallel(&stripe.Event{Type: "customer.created"}); !errors.Is(err, cause) {
+		t.Errorf("Expected HandleParallel error to wrap the handler error, got %v", err)
+	}
+}
+
+func TestHandlerPatterns(t *testing.T) {
+	type testCase struct {
+		event    string
+		expected EventResponse
+		err      error
+	}
+
+	respond := func(res string) StripeEventHandler {
+		return func(_ *stripe.Event) (EventResponse, error) {
+			return res, nil
+		}
+	}
+
+	client := NewClient()
+	client.AppendHandler("invoice.paid", respond("exact"))
+	client.AppendHandler("invoice.*", respond("invoice prefix"))
+	client.AppendHandler("invoice.payment_*", respond("payment prefix"))
+
+	tcs := []testCase{
+		{"invoice.paid", "exact", nil},
+		{"invoice.created", "invoice prefix", nil},
+		{"invoice.payment_failed", "payment prefix", nil},
+		{"customer.created", nil, ErrNoHandler},
+	}
+
+	check := func(tcs []testCase) {
+		for _, tc := range tcs {
+			res, err := client.HandleCollect(&stripe.Event{Type: stripe.EventType(tc.event)})
+			if !errors.Is(err, tc.err) {
+				t.Errorf("Expected error %v for event type = %s, got %v", tc.err, tc.event, err)
+				continue
+			}
+			if err == nil && (len(res) != 1 || res[0] != tc.expected) {
+				t.Errorf("Expected result %v for event type = %s, got %v", tc.expected, tc.event, res)
+			}
+		}
+	}
+	check(tcs)
+
+	client.AppendHandler("*", respond("wildcard"))
+	tcs[3] = testCase{"customer.created", "wildcard", nil}
+	check(tcs)
+}
+
+func TestDefaultSuccessAndFailureHandlers(t *testing.T) {
+	type testCase struct {
+		event   string
+		success string
+		failure string
+	}
+
+	var success, failure string
+
+	client := NewClient()
+	client.AppendHandler("customer.created", func(_ *stripe.Event) (EventResponse, error) {
+		return nil, nil
+	})
+	client.AppendHandler("customer.updated", func(_ *stripe.Event) (EventResponse, error) {
+		return nil, nil
+	})
+	client.AppendHandler("customer.deleted", func(_ *stripe.Event) (EventResponse, error) {
+		return nil, fmt.Errorf("test")
+	})
+	client.AppendHandler("invoice.created", func(_ *stripe.Event) (EventResponse, error) {
+		return nil, fmt.Errorf("test")
+	})
+
+	client.SetDefaultSuccessHandler(func(_ *stripe.Event, _ []EventResponse) error {
+		success = "default"
+		return nil
+	})
+	client.SetDefaultFailureHandler(func(_ *stripe.Event, _ error) error {
+		failure = "default"
+		return nil
+	})
+	client.AddSuccessHandler("customer.updated", func(_ *stripe.Event, _ []EventResponse) error {
+		success = "specific"
+		return nil
+	})
+	client.AddFailureHandler("invoice.created", func(_ *stripe.Event, _ error) error {
+		failure = "specific"
+		return nil
+	})
+
+	tcs := []testCase{
+		{"customer.created", "default", ""},
+		{"customer.updated", "specific", ""},
+		{"customer.deleted", "", "default"},
+		{"invoice.created", "", "specific"},
+	}
+
+	for _, tc := range tcs {
+		for _, handle := range []func(*stripe.Event) error{client.Handle, client.HandleParallel} {
+			success, failure = "", ""
+			if err := handle(&stripe.Event{Type: stripe.EventType(tc.event)}); err != nil {
+				t.Errorf("Event should have NOT failed event type = %s, got %s", tc.event, err)
+			}
+			if success != tc.success {
+				t.Errorf("Expected success handler %q for event type = %s, got %q", tc.success, tc.event, success)
+			}
+			if failure != tc.failure {
+				t.Errorf("Expected failure handler %q for event type = %s, got %q", tc.failure, tc.event, failure)
+			}
+		}
+	}
+}
+
+func TestPanicRecovery(t *testing.T) {
+	type testCase struct {
+		name string
+		cfgs []func(*Client)
+	}
+
+	tcs := []testCase{
+		{"without timeout", []func(*Client){WithPanicRecovery()}},
+		{"with timeout", []func(*Client){WithPanicRecovery(), WithHandlerTimeout(time.Second)}},
+	}
+
+	for _, tc := range tcs {
+		client := NewClient(tc.cfgs...)
+		client.AppendHandler("customer.created", func(_ *stripe.Event) (EventResponse, error) {
+			var m map[string]int
+			m["boom"]++
+			return nil, nil
+		}, func(_ *stripe.Event) (EventResponse, error) {
+			return "ok", nil
+		})
+
+		var failed error
+		client.AddFailureHandler("customer.created", func(_ *stripe.Event, err error) error {
+			failed = err
+			return err
+		})
+
+		for _, handle := range []func(*stripe.Event) error{client.Handle, client.HandleParallel} {
+			failed = nil
+			err := handle(&stripe.Event{Type: "customer.created"})
+			if !errors.Is(err, ErrHandlerPanic) {
+				t.Errorf("Expected %s to return ErrHandlerPanic, got %v", tc.name, err)
+			}
+			if !errors.Is(failed, ErrHandlerPanic) {
+				t.Errorf("Expected %s to route the panic through the failure handler, got %v", tc.name, failed)
+			}
+			if err != nil && !strings.Contains(err.Error(), "assignment to entry in nil map") {
+				t.Errorf("Expected %s to capture the recovered value, got %s", tc.name, err)
+			}
+		}
+	}
+}
+
+func TestAppendHandlerIf(t *testing.T) {
+	type testCase struct {
+		event   stripe.Event
+		results []EventResponse
+	}
+
+	canceled := func(e *stripe.Event) bool {
+		return e.Data != nil && e.Data.Object["status"] == "canceled"
+	}
+
+	client := NewClient()
+	client.AppendHandler("customer.subscription.updated", func(_ *stripe.Event) (EventResponse, error) {
+		return "always", nil
+	})
+	client.AppendHandlerIf("customer.subscription.updated", canceled, func(_ *stripe.Event) (EventResponse, error) {
+		return "canceled", nil
+	})
+
+	var got []EventResponse
+	client.AddSuccessHandler("customer.subscription.updated", func(_ *stripe.Event, results []EventResponse) error {
+		got = results
+		return nil
+	})
+
+	tcs := []testCase{
+		{stripe.Event{Type: "customer.subscription.updated", Data: &stripe.EventData{Object: map[string]interface{}{"status": "canceled"}}}, []EventResponse{"always", "canceled"}},
+		{stripe.Event{Type: "customer.subscription.updated", Data: &stripe.EventData{Object: map[string]interface{}{"status": "active"}}}, []EventResponse{"always"}},
+	}
+
+	for _, tc := range tcs {
+		for _, handle := range []func(*stripe.Event) error{client.Handle, client.HandleParallel} {
+			got = nil
+			if err := handle(&tc.event); err != nil {
+				t.Errorf("Event should have NOT failed event type = %s, got %s", tc.event.Type, err)
+			}
+			if len(got) != len(tc.results) {
+				t.Errorf("Expected results %v, got %v", tc.results, got)
+				continue
+			}
+			for i := range got {
+				if got[i] != tc.results[i] {
+					t.Errorf("Expected result %v at index %d, got %v", tc.results[i], i, got[i])
+				}
+			}
+		}
+	}
+}
+
+func TestLivemodeFiltering(t *testing.T) {
+	type testCase struct {
+		name     string
+		cfgs     []func(*Client)
+		livemode bool
+		calls    int
+	}
+
+	tcs := []testCase{
+		{"no filter live", nil, true, 2},
+		{"no filter test", nil, false, 2},
+		{"livemode only live", []func(*Client){WithLivemodeOnly()}, true, 2},
+		{"livemode only test", []func(*Client){WithLivemodeOnly()}, false, 0},
+		{"testmode only live", []func(*Client){WithTestmodeOnly()}, true, 0},
+		{"testmode only test", []func(*Client){WithTestmodeOnly()}, false, 2},
+	}
+
+	for _, tc := range tcs {
+		calls := 0
+		deduper := NewMemoryDeduper(10, 0)
+		client := NewClient(append(tc.cfgs, WithDeduper(deduper))...)
+		client.AppendHandler("customer.created", func(_ *stripe.Event) (EventResponse, error) {
+			calls++
+			return nil, nil
+		})
+
+		event := stripe.Event{Type: "customer.created", Livemode: tc.livemode}
+		for _, handle := range []func(*stripe.Event) error{client.Handle, client.HandleParallel} {
+			if err := handle(&event); err != nil {
+				t.Errorf("Expected %s to NOT fail, got %s", tc.name, err)
+			}
+		}
+		if calls != tc.calls {
+			t.Errorf("Expected %s to run the handler %d times, ran %d", tc.name, tc.calls, calls)
+		}
+
+		event.ID = "evt_filtered"
+		_ = client.Handle(&event)
+		if seen, _ := deduper.Seen(event.ID); seen != (tc.calls > 0) {
+			t.Errorf("Expected %s to reach the deduper = %t", tc.name, tc.calls > 0)
+		}
+	}
+}
+
+func TestHandlerRetry(t *testing.T) {
+	type testCase struct {
+		name       string
+		attempts   int
+		failures   int
+		calls      int
+		shouldFail bool
+	}
+
+	tcs := []testCase{
+		{"no retry", 0, 1, 1, true},
+		{"succeeds on third attempt", 3, 2, 3, false},
+		{"exhausted", 3, 5, 3, true},
+	}
+
+	for _, tc := range tcs {
+		calls := 0
+		var delays []int
+		client := NewClient(WithHandlerRetry(tc.attempts, func(attempt int) time.Duration {
+			delays = append(delays, attempt)
+			return time.Millisecond
+		}))
+		client.AppendHandler("customer.created", func(_ *stripe.Event) (EventResponse, error) {
+			calls++
+			if calls <= tc.failures {
+				return nil, fmt.Errorf("trello 502")
+			}
+			return "ok", nil
+		})
+
+		err := client.Handle(&stripe.Event{Type: "customer.created"})
+		if err != nil && !tc.shouldFail {
+			t.Errorf("Expected %s to NOT fail, got %s", tc.name, err)
+		}
+		if err == nil && tc.shouldFail {
+			t.Errorf("Expected %s to fail", tc.name)
+		}
+		if calls != tc.calls {
+			t.Errorf("Expected %s to run the handler %d times, ran %d", tc.name, tc.calls, calls)
+		}
+		if tc.attempts > 1 && len(delays) != tc.calls-1 {
+			t.Errorf("Expected %s to back off %d times, got %v", tc.name, tc.calls-1, delays)
+		}
+	}
+}
+
+func TestHandlerRetryCancelled(t *testing.T) {
+	calls := 0
+	client := NewClient(WithHandlerRetry(5, func(int) time.Duration {
+		return time.Hour
+	}))
+	client.AppendHandler("customer.created", func(_ *stripe.Event) (EventResponse, error) {
+		calls++
+		return nil, fmt.Errorf("trello 502")
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	err := client.HandleContext(ctx, &stripe.Event{Type: "customer.created"})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the retry to stop with the context, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("Expected a single attempt before the context expired, got %d", calls)
+	}
+}
+
+func TestContinueOnError(t *testing.T) {
+	ran := []int{}
+	handler := func(i int, fail bool) StripeEventHandler {
+		return func(_ *stripe.Event) (EventResponse, error) {
+			ran = append(ran, i)
+			if fail {
+				return nil, fmt.Errorf("handler %d failed", i)
+			}
+			return i, nil
+		}
+	}
+
+	client := NewClient(WithContinueOnError())
+	client.AppendHandler("customer.created", handler(1, true), handler(2, false), handler(3, true))
+	client.AppendHandler("customer.updated", handler(1, true), handler(2, false), handler(3, true))
+	client.AddFailureHandler("customer.updated", func(_ *stripe.Event, _ error) error {
+		return nil
+	})
+
+	var succeeded []EventResponse
+	client.AddSuccessHandler("customer.updated", func(_ *stripe.Event, results []EventResponse) error {
+		succeeded = results
+		return nil
+	})
+
+	err := client.Handle(&stripe.Event{Type: "customer.created"})
+	if len(ran) != 3 {
+		t.Errorf("Expected all three handlers to run, ran %v", ran)
+	}
+
+	var errs StripeEventErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("Expected StripeEventErrors, got %v", err)
+	}
+	if len(errs) != 2 {
+		t.Errorf("Expected 2 errors, got %d", len(errs))
+	}
+
+	ran = nil
+	if err := client.Handle(&stripe.Event{Type: "customer.updated"}); err != nil {
+		t.Errorf("Expected the failure handler to resolve the errors, got %s", err)
+	}
+	if len(succeeded) != 1 || succeeded[0] != 2 {
+		t.Errorf("Expected the success handler to get the successful result, got %v", succeeded)
+	}
+}
+
+func TestSuccessHandlerWithErrors(t *testing.T) {
+	type testCase struct {
+		name      string
+		cfgs      []func(*Client)
+		event     string
+		called    bool
+		responses int
+		errs      int
+	}
+
+	tcs := []testCase{
+		{"continue on error partial failure", []func(*Client){WithContinueOnError()}, "customer.created", true, 1, 2},
+		{"continue on error success", []func(*Client){WithContinueOnError()}, "customer.updated", true, 2, 0},
+		{"fail fast partial failure", nil, "customer.created", false, 0, 0},
+		{"fail fast success", nil, "customer.updated", true, 2, 0},
+	}
+
+	for _, tc := range tcs {
+		client := NewClient(tc.cfgs...)
+		client.AppendHandler("customer.created", func(_ *stripe.Event) (EventResponse, error) {
+			return nil, fmt.Errorf("first failed")
+		}, func(_ *stripe.Event) (EventResponse, error) {
+			return "ok", nil
+		}, func(_ *stripe.Event) (EventResponse, error) {
+			return nil, fmt.Errorf("third failed")
+		})
+		client.AppendHandler("customer.updated", func(_ *stripe.Event) (EventResponse, error) {
+			return "ok", nil
+		}, func(_ *stripe.Event) (EventResponse, error) {
+			return "ok", nil
+		})
+
+		for _, handle := range []func(*stripe.Event) error{client.Handle, client.HandleParallel} {
+			called := false
+			var responses []EventResponse
+			var errs StripeEventErrors
+			client.AddSuccessHandlerWithErrors(tc.event, func(_ *stripe.Event, res []EventResponse, e StripeEventErrors) (EventResponse, error) {
+				called, responses, errs = true, res, e
+				return "summary", nil
+			})
+
+			err := handle(&stripe.Event{Type: stripe.EventType(tc.event)})
+			if called != tc.called {
+				t.Errorf("Expected %s to call the handler = %t", tc.name, tc.called)
+			}
+			if called && err != nil {
+				t.Errorf("Expected %s to return the handler error, got %s", tc.name, err)
+			}
+			if len(responses) != tc.responses || len(errs) != tc.errs {
+				t.Errorf("Expected %s to see %d responses and %d errors, got %d and %d", tc.name, tc.responses, tc.errs, len(responses), len(errs))
+			}
+		}
+	}
+}
+
+func TestNilHandlers(t *testing.T) {
+	client := NewClient()
+	client.AppendHandler("customer.created", nil, func(_ *stripe.Event) (EventResponse, error) {
+		return "ok", nil
+	})
+	client.AppendHandlerCtx("customer.created", nil)
+	client.AddSuccessHandler("customer.created", nil)
+	client.AddFailureHandler("customer.created", nil)
+
+	if n := client.HandlerCount("customer.created"); n != 1 {
+		t.Errorf("Expected nil handlers to be skipped, got %d handlers", n)
+	}
+
+	for _, handle := range []func(*stripe.Event) error{client.Handle, client.HandleParallel} {
+		if err := handle(&stripe.Event{Type: "customer.created"}); err != nil {
+			t.Errorf("Event should have NOT failed, got %s", err)
+		}
+	}
+
+	if err := client.AppendHandlerChecked("customer.updated", nil); !errors.Is(err, ErrNilHandler) {
+		t.Errorf("Expected AppendHandlerChecked to return ErrNilHandler, got %v", err)
+	}
+	if client.HasHandler("customer.updated") {
+		t.Errorf("Expected AppendHandlerChecked to register nothing on error")
+	}
+	if err := client.AddSuccessHandlerChecked("customer.updated", nil); !errors.Is(err, ErrNilHandler) {
+		t.Errorf("Expected AddSuccessHandlerChecked to return ErrNilHandler, got %v", err)
+	}
+	if err := client.AddFailureHandlerChecked("customer.updated", nil); !errors.Is(err, ErrNilHandler) {
+		t.Errorf("Expected AddFailureHandlerChecked to return ErrNilHandler, got %v", err)
+	}
+}
+
+func TestHandlerPriority(t *testing.T) {
+	var order []string
+	handler := func(name string) StripeEventHandler {
+		return func(_ *stripe.Event) (EventResponse, error) {
+			order = append(order, name)
+			return name, nil
+		}
+	}
+
+	client := NewClient()
+	client.AppendHandler("customer.created", handler("default"))
+	client.AppendHandlerWithPriority("customer.created", 10, handler("last"))
+	client.AppendHandlerWithPriority("customer.created", -10, handler("board"))
+	client.AppendHandler("customer.created", handler("default 2"))
+	client.AppendHandlerWithPriority("customer.created", -10, handler("board 2"))
+
+	results, err := client.HandleCollect(&stripe.Event{Type: "customer.created"})
+	if err != nil {
+		t.Fatalf("Event should have NOT failed, got %s", err)
+	}
+
+	expected := []string{"board", "board 2", "default", "default 2", "last"}
+	if strings.Join(order, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected handlers to run in order %v, got %v", expected, order)
+	}
+	for i, res := range results {
+		if res != expected[i] {
+			t.Errorf("Expected result %d to be %s, got %v", i, expected[i], res)
+		}
+	}
+}
+
+func TestParallelCancelOnError(t *testing.T) {
+	cause := errors.New("trello unavailable")
+
+	var cancelled int32
+	client := NewClient(WithParallelCancelOnError())
+	client.AppendHandlerCtx("customer.created", func(_ context.Context, _ *stripe.Event) (EventResponse, error) {
+		return nil, cause
+	})
+	for i := 0; i < 3; i++ {
+		client.AppendHandlerCtx("customer.created", func(ctx context.Context, _ *stripe.Event) (EventResponse, error) {
+			select {
+			case <-ctx.Done():
+				atomic.AddInt32(&cancelled, 1)
+				return nil, ctx.Err()
+			case <-time.After(time.Second):
+				return "ok", nil
+			}
+		})
+	}
+	client.AppendHandler("customer.created", func(_ *stripe.Event) (EventResponse, error) {
+		time.Sleep(time.Second)
+		return "ok", nil
+	})
+
+	start := time.Now()
+	err := client.HandleParallel(&stripe.Event{Type: "customer.created"})
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Expected HandleParallel to return early, took %s", elapsed)
+	}
+	if !errors.Is(err, cause) {
+		t.Errorf("Expected the first handler error, got %v", err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for atomic.LoadInt32(&cancelled) != 3 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if got := atomic.LoadInt32(&cancelled); got != 3 {
+		t.Errorf("Expected the context aware handlers to be cancelled, got %d", got)
+	}
+}
+
+func TestRegisterHandlers(t *testing.T) {
+	type testCase struct {
+		event string
+		count int
+	}
+
+	noop := func(_ *stripe.Event) (EventResponse, error) {
+		return nil, nil
+	}
+
+	client := NewClient()
+	client.AppendHandler("customer.created", noop)
+	client.RegisterHandlers(map[string][]StripeEventHandler{
+		"customer.created": {noop, noop},
+		"customer.updated": {noop},
+		"invoice.paid":     {noop, noop, noop},
+	})
+
+	failed := false
+	client.RegisterSuccessHandlers(map[string]StripeSuccessEventHandler{
+		"customer.updated": func(_ *stripe.Event, _ []EventResponse) error { return fmt.Errorf("success handler") },
+	})
+	client.RegisterFailureHandlers(map[string]StripeFailedEventHandler{
+		"customer.updated": func(_ *stripe.Event, _ error) error { failed = true; return nil },
+	})
+
+	tcs := []testCase{
+		{"customer.created", 3},
+		{"customer.updated", 1},
+		{"invoice.paid", 3},
+		{"invoice.created", 0},
+	}
+
+	for _, tc := range tcs {
+		if n := client.HandlerCount(tc.event); n != tc.count {
+			t.Errorf("Expected %d handlers for %s, got %d", tc.count, tc.event, n)
+		}
+	}
+
+	if err := client.Handle(&stripe.Event{Type: "customer.updated"}); err == nil || err.Error() != "success handler" {
+		t.Errorf("Expected the registered success handler to run, got %v", err)
+	}
+	if failed {
+		t.Errorf("Expected the registered failure handler to NOT run")
+	}
+}
+
+func TestNewClientStrict(t *testing.T) {
+	type testCase struct {
+		name       string
+		cfgs       []func(*Client)
+		shouldFail bool
+	}
+
+	tcs := []testCase{
+		{"missing secret", nil, true},
+		{"empty secret", []func(*Client){WithStripeWebhookSecret("")}, true},
+		{"secret", []func(*Client){WithStripeWebhookSecret(testSecret)}, false},
+		{"rotated secrets", []func(*Client){WithStripeWebhookSecrets("", testSecret)}, false},
+		{"insecure skip verify", []func(*Client){WithInsecureSkipVerify()}, false},
+		{"verifier", []func(*Client){WithVerifier(&mockVerifier{})}, false},
+	}
+
+	for _, tc := range tcs {
+		client, err := NewClientStrict(tc.cfgs...)
+		if tc.shouldFail {
+			if !errors.Is(err, ErrNoWebhookSecret) || client != nil {
+				t.Errorf("Expected %s to fail with ErrNoWebhookSecret, got %v", tc.name, err)
+			}
+			if err := NewClient(tc.cfgs...).Validate(); !errors.Is(err, ErrNoWebhookSecret) {
+				t.Errorf("Expected Validate to fail for %s, got %v", tc.name, err)
+			}
+			continue
+		}
+		if err != nil || client == nil {
+			t.Errorf("Expected %s to NOT fail, got %s", tc.name, err)
+		}
+	}
+}
+
+func TestAppendSuccessHandler(t *testing.T) {
+	var calls []string
+	success := func(name string) StripeSuccessEventHandler {
+		return func(_ *stripe.Event, results []EventResponse) error {
+			calls = append(calls, fmt.Sprintf("%s:%v", name, results))
+			return nil
+		}
+	}
+
+	client := NewClient()
+	client.AppendHandler("customer.created", func(_ *stripe.Event) (EventResponse, error) {
+		return "ok", nil
+	})
+	client.AppendSuccessHandler("customer.created", success("billing"))
+	client.AppendSuccessHandler("customer.created", success("trello"))
+
+	for _, handle := range []func(*stripe.Event) error{client.Handle, client.HandleParallel} {
+		calls = nil
+		if err := handle(&stripe.Event{Type: "customer.created"}); err != nil {
+			t.Errorf("Event should have NOT failed, got %s", err)
+		}
+		if strings.Join(calls, ",") != "billing:[ok],trello:[ok]" {
+			t.Errorf("Expected both success handlers to run in order with the results, got %v", calls)
+		}
+	}
+
+	calls = nil
+	client.AddSuccessHandler("customer.created", success("replaced"))
+	client.Handle(&stripe.Event{Type: "customer.created"})
+	if strings.Join(calls, ",") != "replaced:[ok]" {
+		t.Errorf("Expected AddSuccessHandler to replace the chain, got %v", calls)
+	}
+}
+
+func TestMaxConcurrencyFor(t *testing.T) {
+	type testCase struct {
+		event string
+		limit int32
+	}
+
+	tcs := []testCase{
+		{"invoice.created", 1},
+		{"charge.succeeded", 4},
+		{"customer.created", 2},
+	}
+
+	client := NewClient(WithMaxConcurrency(2), WithMaxConcurrencyFor("invoice.*", 1), WithMaxConcurrencyFor("charge.*", 4))
+	for _, tc := range tcs {
+		var running, peak int32
+		handler := func(_ *stripe.Event) (EventResponse, error) {
+			n := atomic.AddInt32(&running, 1)
+			for {
+				p := atomic.LoadInt32(&peak)
+				if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			atomic.AddInt32(&running, -1)
+			return "done", nil
+		}
+		for i := 0; i < 8; i++ {
+			client.AppendHandler(tc.event, handler)
+		}
+
+		if err := client.HandleParallel(&stripe.Event{Type: stripe.EventType(tc.event)}); err != nil {
+			t.Errorf("Event should have NOT failed event type = %s, got %s", tc.event, err)
+		}
+		if p := atomic.LoadInt32(&peak); p > tc.limit {
+			t.Errorf("Expected at most %d %s handlers running simultaneously, got %d", tc.limit, tc.event, p)
+		}
+		if p := atomic.LoadInt32(&peak); tc.limit > 2 && p <= 2 {
+			t.Errorf("Expected the %s limit to override the global one, got %d handlers running simultaneously", tc.event, p)
+		}
+	}
+}
+
+func TestMaxConcurrencyForIsSharedAcrossEvents(t *testing.T) {
+	client := NewClient(WithMaxConcurrencyFor("invoice.*", 1))
+
+	var running, peak int32
+	handler := func(_ *stripe.Event) (EventResponse, error) {
+		n := atomic.AddInt32(&running, 1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		atomic.AddInt32(&running, -1)
+		return "done", nil
+	}
+	client.AppendHandler("invoice.created", handler)
+	client.AppendHandler("invoice.created", handler)
+	client.AppendHandler("invoice.paid", handler)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		for _, eventType := range []stripe.EventType{"invoice.created", "invoice.paid"} {
+			wg.Add(1)
+			go func(eventType stripe.EventType) {
+				defer wg.Done()
+				if err := client.HandleParallel(&stripe.Event{Type: eventType}); err != nil {
+					t.Errorf("Event should have NOT failed event type = %s, got %s", eventType, err)
+				}
+			}(eventType)
+		}
+	}
+	wg.Wait()
+
+	if p := atomic.LoadInt32(&peak); p != 1 {
+		t.Errorf("Expected the invoice.* limit to be shared by all the events, got %d handlers running simultaneously", p)
+	}
+}
+
+func TestHandleErrorTypeMatchesParallel(t *testing.T) {
+	type testCase struct {
+		name   string
+		client *Client
+	}
+
+	failing := func(_ *stripe.Event) (interface{}, error) {
+		return nil, fmt.Errorf("test")
+	}
+
+	tcs := []testCase{
+		{"fail fast", NewClient()},
+		{"continue on error", NewClient(WithContinueOnError())},
+	}
+
+	for _, tc := range tcs {
+		tc.client.AppendHandler("customer.created", failing)
+
+		sErr := tc.client.Handle(&stripe.Event{ID: "evt_1", Type: "customer.created"})
+		pErr := tc.client.HandleParallel(&stripe.Event{ID: "evt_2", Type: "customer.created"})
+
+		if reflect.TypeOf(sErr) != reflect.TypeOf(pErr) {
+			t.Errorf("%s: Expected the same error type got %T and %T", tc.name, sErr, pErr)
+		}
+
+		for _, err := range []error{sErr, pErr} {
+			var see StripeEventError
+			if !errors.As(err, &see) {
+				t.Fatalf("%s: Expected a StripeEventError got %T", tc.name, err)
+			}
+			errs, ok := errors.Unwrap(see).(StripeEventErrors)
+			if !ok || len(errs) != 1 {
+				t.Errorf("%s: Expected StripeEventErrors with 1 entry got %#v", tc.name, errors.Unwrap(see))
+			}
+		}
+	}
+}
+
+func TestDedupeHandlers(t *testing.T) {
+	type testCase struct {
+		name     string
+		cfgs     []func(*Client)
+		expected int32
+	}
+
+	var calls atomic.Int32
+	handler := func(_ *stripe.Event) (interface{}, error) {
+		calls.Add(1)
+		return nil, nil
+	}
+
+	tcs := []testCase{
+		{"default", nil, 2},
+		{"dedupe", []func(*Client){WithDedupeHandlers()}, 1},
+	}
+
+	for _, tc := range tcs {
+		calls.Store(0)
+		client := NewClient(tc.cfgs...)
+		client.AppendHandler("customer.created", handler)
+		client.AppendHandler("customer.created", handler)
+		client.AppendHandler("customer.updated", handler)
+
+		if err := client.Handle(&stripe.Event{ID: "evt_1", Type: "customer.created"}); err != nil {
+			t.Fatalf("%s: Event should have NOT failed %v", tc.name, err)
+		}
+		if got := calls.Load(); got != tc.expected {
+			t.Errorf("%s: Expected %d executions got %d", tc.name, tc.expected, got)
+		}
+		if got := client.HandlerCount("customer.updated"); got != 1 {
+			t.Errorf("%s: Expected 1 handler for customer.updated got %d", tc.name, got)
+		}
+	}
+}
+
+func TestHandleParallelAccounting(t *testing.T) {
+	type testCase struct {
+		name      string
+		handlers  []StripeEventHandler
+		run       bool
+		failed    int
+		responses int
+	}
+
+	ok := func(_ *stripe.Event) (interface{}, error) {
+		return "ok", nil
+	}
+	failing := func(_ *stripe.Event) (interface{}, error) {
+		return nil, fmt.Errorf("test")
+	}
+
+	tcs := []testCase{
+		{"all success", []StripeEventHandler{ok, ok, ok}, true, 0, 3},
+		{"partial failure", []StripeEventHandler{ok, failing, ok}, true, 1, 2},
+		{"zero handlers", []StripeEventHandler{ok, ok}, false, 0, 0},
+	}
+
+	for _, tc := range tcs {
+		client := NewClient(WithContinueOnError())
+		var responses int
+		// Every handler is skipped in the zero handlers case.
+		client.AppendHandlerIf("customer.created", func(*stripe.Event) bool { return tc.run }, tc.handlers...)
+		client.AddSuccessHandler("customer.created", func(_ *stripe.Event, res []EventResponse) error {
+			responses = len(res)
+			return nil
+		})
+		client.AddFailureHandler("customer.created", func(_ *stripe.Event, err error) error {
+			var errs StripeEventErrors
+			if !errors.As(err, &errs) || len(errs) != tc.failed {
+				t.Errorf("%s: Expected %d failures got %v", tc.name, tc.failed, err)
+			}
+			return nil
+		})
+
+		if err := client.HandleParallel(&stripe.Event{ID: "evt_1", Type: "customer.created"}); err != nil {
+			t.Errorf("%s: Event should have NOT failed %v", tc.name, err)
+		}
+		if responses != tc.responses {
+			t.Errorf("%s: Expected %d responses got %d", tc.name, tc.responses, responses)
+		}
+	}
+}
+
+func TestAppendHandlerIfNoneSelected(t *testing.T) {
+	client := NewClient()
+	client.AppendHandlerIf("customer.created", func(*stripe.Event) bool { return false }, func(_ *stripe.Event) (EventResponse, error) {
+		return "created", nil
+	})
+	client.AddSuccessHandler("customer.created", func(_ *stripe.Event, results []EventResponse) error {
+		t.Errorf("Expected the success handler to NOT be called without a selected handler, got %v", results)
+		return nil
+	})
+
+	for _, handle := range []func(*stripe.Event) error{client.Handle, client.HandleParallel} {
+		if err := handle(&stripe.Event{ID: "evt_test", Type: "customer.created"}); err != nil {
+			t.Errorf("Event should have NOT failed event type = customer.created, got %s", err)
+		}
+	}
+	if count := client.ProcessedCount(); count != 0 {
+		t.Errorf("Expected skipped events to NOT be counted as processed, got %d", count)
+	}
+	if id, _ := client.LastProcessed(); id != "" {
+		t.Errorf("Expected no last processed event, got %s", id)
+	}
+}
+
+func TestCheckAccounting(t *testing.T) {
+	type testCase struct {
+		handlers, succeeded, errored int
+		shouldFail                   bool
+	}
+
+	tcs := []testCase{
+		{3, 3, 0, false},
+		{3, 1, 2, false},
+		{0, 0, 0, false},
+		{3, 1, 1, true},
+	}
+
+	for _, tc := range tcs {
+		err := checkAccounting(tc.handlers, tc.succeeded, tc.errored)
+		if err != nil && !tc.shouldFail {
+			t.Errorf("Expected no error for %+v got %v", tc, err)
+		}
+		if err == nil && tc.shouldFail {
+			t.Errorf("Expected an error for %+v", tc)
+		}
+		if err != nil && !strings.Contains(err.Error(), "1 succeeded, 1 errored") {
+			t.Errorf("Expected the counts in the error got %q", err)
+		}
+	}
+}
+
+func TestEmptyHandlerSlice(t *testing.T) {
+	client := NewClient()
+	client.mu.Lock()
+	client.handlers["customer.created"] = []registeredHandler{}
+	client.mu.Unlock()
+
+	if err := client.Handle(&stripe.Event{ID: "evt_1", Type: "customer.created"}); !errors.Is(err, ErrNoHandler) {
+		t.Errorf("Expected ErrNoHandler from Handle got %v", err)
+	}
+	if err := client.HandleParallel(&stripe.Event{ID: "evt_2", Type: "customer.created"}); !errors.Is(err, ErrNoHandler) {
+		t.Errorf("Expected ErrNoHandler from HandleParallel got %v", err)
+	}
+	if types := client.RegisteredEventTypes(); len(types) != 0 {
+		t.Errorf("Expected no registered event types got %v", types)
+	}
+
+	if client.RemoveHandler("customer.created") {
+		t.Errorf("Expected RemoveHandler to report nothing removed for an empty entry")
+	}
+	if _, ok := client.handlers["customer.created"]; ok {
+		t.Errorf("Expected RemoveHandler to delete the empty entry")
+	}
+}
+
+func TestHandleParallelSingleHandler(t *testing.T) {
+	type testCase struct {
+		name       string
+		cfgs       []func(*Client)
+		fail       bool
+		cancelled  bool
+		shouldFail bool
+		calls      int32
+	}
+
+	tcs := []testCase{
+		{"success", nil, false, false, false, 1},
+		{"failure", nil, true, false, true, 1},
+		{"failure cancel on error", []func(*Client){WithParallelCancelOnError()}, true, false, true, 1},
+		{"cancelled context", nil, false, true, true, 0},
+	}
+
+	for _, tc := range tcs {
+		var calls atomic.Int32
+		var responses []EventResponse
+		client := NewClient(tc.cfgs...)
+		client.AppendHandler("customer.created", func(_ *stripe.Event) (EventResponse, error) {
+			calls.Add(1)
+			if tc.fail {
+				return nil, fmt.Errorf("test")
+			}
+			return "ok", nil
+		})
+		client.AddSuccessHandler("customer.created", func(_ *stripe.Event, res []EventResponse) error {
+			responses = res
+			return nil
+		})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		if tc.cancelled {
+			cancel()
+		}
+		err := client.HandleParallelContext(ctx, &stripe.Event{ID: "evt_1", Type: "customer.created"})
+		cancel()
+
+		if err != nil && !tc.shouldFail {
+			t.Errorf("%s: Event should have NOT failed %v", tc.name, err)
+		}
+		if err == nil && tc.shouldFail {
+			t.Errorf("%s: Event should have failed", tc.name)
+		}
+		var errs StripeEventErrors
+		if tc.shouldFail && (!errors.As(err, &errs) || len(errs) != 1) {
+			t.Errorf("%s: Expected a single failure got %v", tc.name, err)
+		}
+		if tc.cancelled && !errors.Is(err, context.Canceled) {
+			t.Errorf("%s: Expected context.Canceled got %v", tc.name, err)
+		}
+		if !tc.shouldFail && (len(responses) != 1 || responses[0] != "ok") {
+			t.Errorf("%s: Expected the handler response got %v", tc.name, responses)
+		}
+		if got := calls.Load(); got != tc.calls {
+			t.Errorf("%s: Expected %d calls got %d", tc.name, tc.calls, got)
+		}
+	}
+}
+
+func BenchmarkHandleParallelSingle(b *testing.B) {
+	client := NewClient()
+	client.AppendHandler("customer.created", func(_ *stripe.Event) (EventResponse, error) {
+		return nil, nil
+	})
+	event := &stripe.Event{ID: "evt_1", Type: "customer.created"}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if err := client.HandleParallel(event); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkHandleParallelAllFail(b *testing.B) {
+	failing := func(_ *stripe.Event) (EventResponse, error) {
+		return nil, errors.New("trello is down")
+	}
+
+	client := NewClient(WithContinueOnError())
+	client.AppendHandler("customer.created", failing, failing, failing, failing, failing, failing, failing, failing)
+	client.AddFailureHandler("customer.created", func(_ *stripe.Event, _ error) error {
+		return nil
+	})
+	event := &stripe.Event{ID: "evt_1", Type: "customer.created"}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if err := client.HandleParallel(event); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+type invoiceError struct {
+	invoice string
+	err     error
+}
+
+func (e invoiceError) Error() string {
+	return fmt.Sprintf("invoice %s: %s", e.invoice, e.err)
+}
+
+func (e invoiceError) Unwrap() error {
+	return e.err
+}
+
+func TestFailureHandlerTransformsError(t *testing.T) {
+	type testCase struct {
+		name   string
+		cfgs   []func(*Client)
+		handle func(*Client, *stripe.Event) error
+	}
+
+	sequential := func(c *Client, e *stripe.Event) error { return c.Handle(e) }
+	parallel := func(c *Client, e *stripe.Event) error { return c.HandleParallel(e) }
+
+	tcs := []testCase{
+		{"sequential", nil, sequential},
+		{"sequential continue on error", []func(*Client){WithContinueOnError()}, sequential},
+		{"parallel", nil, parallel},
+		{"parallel cancel on error", []func(*Client){WithParallelCancelOnError()}, parallel},
+	}
+
+	cause := errors.New("trello is down")
+	for _, tc := range tcs {
+		client := NewClient(tc.cfgs...)
+		client.AppendHandler("invoice.payment_failed", func(_ *stripe.Event) (EventResponse, error) {
+			return nil, cause
+		}, func(_ *stripe.Event) (EventResponse, error) {
+			return "ok", nil
+		})
+		client.AddFailureHandler("invoice.payment_failed", func(_ *stripe.Event, err error) error {
+			return invoiceError{"in_1", err}
+		})
+
+		err := tc.handle(client, &stripe.Event{ID: "evt_1", Type: "invoice.payment_failed"})
+		var ie invoiceError
+		if !errors.As(err, &ie) || ie.invoice != "in_1" {
+			t.Errorf("%s: Expected the failure handler error got %v", tc.name, err)
+		}
+		if _, ok := err.(invoiceError); !ok {
+			t.Errorf("%s: Expected the failure handler error as is got %T", tc.name, err)
+		}
+		if !errors.Is(err, cause) {
+			t.Errorf("%s: Expected the handler error to stay wrapped got %v", tc.name, err)
+		}
+	}
+}
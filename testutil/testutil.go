@@ -0,0 +1,22 @@
+package testutil
+
+import (
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	stripe "github.com/stripe/stripe-go/v76"
+	"github.com/stripe/stripe-go/v76/webhook"
+)
+
+// SignPayload returns a Stripe-Signature header for payload signed with secret
+// at t, accepted by Client.Event as long as t is within its tolerance.
+func SignPayload(secret string, payload []byte, t time.Time) string {
+	return fmt.Sprintf("t=%d,v1=%s", t.Unix(), hex.EncodeToString(webhook.ComputeSignature(t, payload, secret)))
+}
+
+// Payload returns the payload of an evt_test event of eventType, in the API
+// version of the stripe-go module so Client.Event accepts it.
+func Payload(eventType string) []byte {
+	return []byte(fmt.Sprintf(`{"id": "evt_test", "object": "event", "type": %q, "api_version": %q, "data": {"object": {}}}`, eventType, stripe.APIVersion))
+}
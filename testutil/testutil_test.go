@@ -0,0 +1,63 @@
+package testutil
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/skipper-digital-studio/stripetotrello"
+	stripe "github.com/stripe/stripe-go/v76"
+)
+
+const testSecret = "whsec_test"
+
+func TestSignPayload(t *testing.T) {
+	payload := Payload("customer.created")
+
+	type testCase struct {
+		name   string
+		secret string
+		at     time.Time
+		fail   bool
+	}
+
+	tcs := []testCase{
+		{"valid", testSecret, time.Now(), false},
+		{"other secret", "whsec_other", time.Now(), true},
+		{"too old", testSecret, time.Now().Add(-time.Hour), true},
+	}
+
+	client := stripetotrello.NewClient(stripetotrello.WithStripeWebhookSecret(testSecret))
+	client.AppendHandler("customer.created", func(_ *stripe.Event) (stripetotrello.EventResponse, error) {
+		return "ok", nil
+	})
+
+	for _, tc := range tcs {
+		header := SignPayload(tc.secret, payload, tc.at)
+
+		event, err := client.Event(payload, header)
+		if tc.fail {
+			if !stripetotrello.IsSignatureError(err) {
+				t.Errorf("Expected %s to fail verification, got %v", tc.name, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Event should have NOT failed for %s, got %s", tc.name, err)
+			continue
+		}
+		if event.ID != "evt_test" {
+			t.Errorf("Expected evt_test, got %s", event.ID)
+		}
+
+		req := httptest.NewRequest(http.MethodPost, "/webhook", bytes.NewReader(payload))
+		req.Header.Set(stripetotrello.SIGNATURE_HEADER, header)
+		rec := httptest.NewRecorder()
+		client.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Errorf("Expected status %d for %s, got %d", http.StatusOK, tc.name, rec.Code)
+		}
+	}
+}
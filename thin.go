@@ -0,0 +1,142 @@
+package stripetotrello
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	stripe "github.com/stripe/stripe-go/v76"
+	"github.com/stripe/stripe-go/v76/webhook"
+)
+
+// THIN_EVENT_OBJECT is the object of the v2 event notifications Stripe sends
+// to thin event destinations.
+const THIN_EVENT_OBJECT = "v2.core.event"
+
+type (
+	// EventNotification is a v2 thin event, it only references the object it
+	// is about, use FetchObject on the bridged event to retrieve it.
+	EventNotification struct {
+		ID            string         `json:"id"`
+		Object        string         `json:"object"`
+		Type          string         `json:"type"`
+		Created       time.Time      `json:"created"`
+		Livemode      bool           `json:"livemode"`
+		Context       string         `json:"context,omitempty"`
+		RelatedObject *RelatedObject `json:"related_object,omitempty"`
+	}
+
+	RelatedObject struct {
+		ID   string `json:"id"`
+		Type string `json:"type"`
+		URL  string `json:"url"`
+	}
+
+	thinVerifier struct {
+		*WebhookVerifier
+		insecure bool
+	}
+)
+
+// ParseEventNotification decodes a v2 thin event WITHOUT verifying its
+// signature.
+func ParseEventNotification(raw []byte) (*EventNotification, error) {
+	n, err := decodeNotification(raw)
+	if err != nil {
+		return nil, newError("ParseEventNotification", []interface{}{raw}, err)
+	}
+	return n, nil
+}
+
+func decodeNotification(raw []byte) (*EventNotification, error) {
+	var n EventNotification
+	if err := json.Unmarshal(raw, &n); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrPayloadParse, err)
+	}
+	if n.Object != THIN_EVENT_OBJECT {
+		return nil, fmt.Errorf("%w: object %q is not a thin event", ErrPayloadParse, n.Object)
+	}
+	return &n, nil
+}
+
+// Event bridges the notification to the v1 event handlers are registered
+// for. Its data object only carries the related object id, type and url, the
+// connected account comes from the notification context.
+func (n *EventNotification) Event() *stripe.Event {
+	event := &stripe.Event{
+		ID:       n.ID,
+		Object:   n.Object,
+		Type:     stripe.EventType(n.Type),
+		Created:  n.Created.Unix(),
+		Livemode: n.Livemode,
+		Account:  n.Context,
+		Data:     &stripe.EventData{Object: map[string]interface{}{}},
+	}
+	if n.RelatedObject != nil {
+		event.Data.Object["id"] = n.RelatedObject.ID
+		event.Data.Object["object"] = n.RelatedObject.Type
+		event.Data.Object["url"] = n.RelatedObject.URL
+	}
+	event.Data.Raw, _ = json.Marshal(event.Data.Object)
+	return event
+}
+
+// ThinEvent verifies a v2 thin event with the webhook secrets and bridges it
+// like EventNotification.Event. WithVerifier does not apply, thin events have
+// no API version to check.
+func (st *Client) ThinEvent(raw []byte, signature string) (*stripe.Event, error) {
+	v, _ := st.defaultVerifier(st.stripeWebhookSecrets).(*WebhookVerifier)
+	return st.verify(&thinVerifier{WebhookVerifier: v, insecure: v == nil}, raw, signature)
+}
+
+// HandleThin verifies a v2 thin event and dispatches it with Handle, errors
+// are returned like HandleRaw does.
+func (st *Client) HandleThin(raw []byte, signature string) error {
+	event, err := st.ThinEvent(raw, signature)
+	if err != nil {
+		return NewInvalidEventError(err)
+	}
+	return st.HandleRawEvent(&RawEvent{Event: event, Raw: raw, Signature: signature})
+}
+
+func (v *thinVerifier) Verify(raw []byte, signature string) (*stripe.Event, error) {
+	if !v.insecure {
+		if err := v.validate(raw, signature); err != nil {
+			return nil, err
+		}
+	}
+	n, err := decodeNotification(raw)
+	if err != nil {
+		return nil, err
+	}
+	return n.Event(), nil
+}
+
+func (v *WebhookVerifier) validate(raw []byte, signature string) error {
+	secrets := v.Secrets
+	if len(secrets) == 0 {
+		secrets = []string{""}
+	}
+
+	var err error
+	for _, secret := range secrets {
+		err = webhook.ValidatePayloadIgnoringTolerance(raw, signature, secret)
+		if err == nil {
+			if !v.Options.IgnoreTolerance && v.expired(signature) {
+				return webhook.ErrTooOld
+			}
+			return nil
+		}
+		if err != webhook.ErrNoValidSignature {
+			break
+		}
+	}
+	return err
+}
+
+// thinObjectPath returns the API path of a bridged thin event related object.
+func thinObjectPath(object map[string]interface{}) (string, bool) {
+	url, _ := object["url"].(string)
+	return url, strings.HasPrefix(url, "/v")
+}
@@ -0,0 +1,118 @@
+package stripetotrello
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/skipper-digital-studio/stripetotrello/testutil"
+	stripe "github.com/stripe/stripe-go/v76"
+)
+
+const thinPayload = `{
+	"id": "evt_test_65R1",
+	"object": "v2.core.event",
+	"type": "v1.billing.meter.error_report_triggered",
+	"created": "2024-09-17T06:20:52.246Z",
+	"livemode": false,
+	"context": "acct_123",
+	"related_object": {"id": "mtr_123", "type": "billing.meter", "url": "/v1/billing/meters/mtr_123"}
+}`
+
+func TestParseEventNotification(t *testing.T) {
+	type testCase struct {
+		name     string
+		raw      string
+		sentinel error
+	}
+
+	tcs := []testCase{
+		{"thin event", thinPayload, nil},
+		{"v1 event", string(testutil.Payload("invoice.paid")), ErrPayloadParse},
+		{"invalid json", "{", ErrPayloadParse},
+	}
+
+	for _, tc := range tcs {
+		n, err := ParseEventNotification([]byte(tc.raw))
+		if tc.sentinel != nil {
+			if !errors.Is(err, tc.sentinel) {
+				t.Errorf("Expected %s to fail with %s, got %v", tc.name, tc.sentinel, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Expected %s to NOT fail, got %s", tc.name, err)
+		}
+
+		event := n.Event()
+		if event.ID != "evt_test_65R1" || event.Type != "v1.billing.meter.error_report_triggered" || event.Account != "acct_123" {
+			t.Errorf("Expected %s to bridge the notification, got %+v", tc.name, event)
+		}
+		if event.Created != time.Date(2024, 9, 17, 6, 20, 52, 0, time.UTC).Unix() {
+			t.Errorf("Expected %s to keep the creation time, got %d", tc.name, event.Created)
+		}
+		if event.Data.Object["id"] != "mtr_123" || event.Data.Object["object"] != "billing.meter" {
+			t.Errorf("Expected %s to reference the related object, got %v", tc.name, event.Data.Object)
+		}
+	}
+}
+
+func TestHandleThin(t *testing.T) {
+	var paths []string
+	backend := stripeBackend(t, func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		fmt.Fprint(w, `{"id": "mtr_123", "object": "billing.meter", "display_name": "API calls"}`)
+	})
+
+	type testCase struct {
+		name      string
+		signature string
+		sentinel  error
+	}
+
+	raw := []byte(thinPayload)
+	tcs := []testCase{
+		{"signed", signature(testSecret, raw, time.Now()), nil},
+		{"wrong secret", signature("whsec_other", raw, time.Now()), ErrSignatureVerification},
+		{"too old", signature(testSecret, raw, time.Now().Add(-time.Hour)), ErrSignatureVerification},
+	}
+
+	for _, tc := range tcs {
+		paths = nil
+		client := NewClient(WithStripeWebhookSecret(testSecret), WithStripeAPIKey("sk_test_123"), WithStripeBackend(backend))
+
+		var meter struct {
+			ID          string `json:"id"`
+			DisplayName string `json:"display_name"`
+		}
+		var account string
+		client.AppendHandlerCtx("v1.billing.meter.error_report_triggered", func(ctx context.Context, event *stripe.Event) (EventResponse, error) {
+			account, _ = AccountFromContext(ctx)
+			return nil, client.FetchObject(ctx, event, &meter)
+		})
+
+		err := client.HandleThin(raw, tc.signature)
+		if tc.sentinel != nil {
+			if !errors.Is(err, tc.sentinel) {
+				t.Errorf("Expected %s to fail with %s, got %v", tc.name, tc.sentinel, err)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Fatalf("Expected %s to NOT fail, got %s", tc.name, err)
+		}
+		if account != "acct_123" {
+			t.Errorf("Expected %s to dispatch for the context account, got %q", tc.name, account)
+		}
+		if len(paths) != 1 || paths[0] != "/v1/billing/meters/mtr_123" {
+			t.Errorf("Expected %s to fetch the related object url, got %v", tc.name, paths)
+		}
+		if meter.ID != "mtr_123" || meter.DisplayName != "API calls" {
+			t.Errorf("Expected %s to decode the fetched object, got %+v", tc.name, meter)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package stripetotrello
+
+import (
+	"context"
+
+	stripe "github.com/stripe/stripe-go/v76"
+)
+
+type (
+	// Tracer starts a span for every dispatched event and a child span for
+	// every handler, the returned function ends the span with the outcome.
+	// See the tracing subpackage for an OpenTelemetry implementation.
+	Tracer interface {
+		StartEvent(ctx context.Context, event *stripe.Event) (context.Context, func(err error))
+		StartHandler(ctx context.Context, event *stripe.Event, index int) (context.Context, func(err error))
+	}
+
+	noopTracer struct{}
+)
+
+func (noopTracer) StartEvent(ctx context.Context, _ *stripe.Event) (context.Context, func(error)) {
+	return ctx, func(error) {}
+}
+
+func (noopTracer) StartHandler(ctx context.Context, _ *stripe.Event, _ int) (context.Context, func(error)) {
+	return ctx, func(error) {}
+}
+
+func WithTracer(t Tracer) func(*Client) {
+	return func(c *Client) {
+		if t == nil {
+			t = noopTracer{}
+		}
+		c.tracer = t
+	}
+}
@@ -0,0 +1,57 @@
+package tracing
+
+import (
+	"context"
+
+	stripe "github.com/stripe/stripe-go/v76"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/skipper-digital-studio/stripetotrello"
+)
+
+const INSTRUMENTATION_NAME = "github.com/skipper-digital-studio/stripetotrello"
+
+type Tracer struct {
+	tracer trace.Tracer
+}
+
+// WithTracerProvider instruments the client with a root span per dispatched
+// event and a child span per handler.
+func WithTracerProvider(tp trace.TracerProvider) func(*stripetotrello.Client) {
+	return stripetotrello.WithTracer(NewTracer(tp))
+}
+
+func NewTracer(tp trace.TracerProvider) *Tracer {
+	return &Tracer{
+		tracer: tp.Tracer(INSTRUMENTATION_NAME),
+	}
+}
+
+func (t *Tracer) StartEvent(ctx context.Context, event *stripe.Event) (context.Context, func(error)) {
+	ctx, span := t.tracer.Start(ctx, "stripe.event "+string(event.Type), trace.WithAttributes(
+		attribute.String("stripe.event.id", event.ID),
+		attribute.String("stripe.event.type", string(event.Type)),
+	))
+	return ctx, end(span)
+}
+
+func (t *Tracer) StartHandler(ctx context.Context, event *stripe.Event, index int) (context.Context, func(error)) {
+	ctx, span := t.tracer.Start(ctx, "stripe.handler "+string(event.Type), trace.WithAttributes(
+		attribute.String("stripe.event.id", event.ID),
+		attribute.String("stripe.event.type", string(event.Type)),
+		attribute.Int("stripe.handler.index", index),
+	))
+	return ctx, end(span)
+}
+
+func end(span trace.Span) func(error) {
+	return func(err error) {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}
+}
@@ -0,0 +1,72 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	stripe "github.com/stripe/stripe-go/v76"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/skipper-digital-studio/stripetotrello"
+)
+
+func TestWithTracerProvider(t *testing.T) {
+	type testCase struct {
+		name   string
+		parent string
+		index  int64
+		status codes.Code
+	}
+
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	client := stripetotrello.NewClient(WithTracerProvider(tp))
+	client.AppendHandler("invoice.paid", func(_ *stripe.Event) (stripetotrello.EventResponse, error) {
+		return "ok", nil
+	}, func(_ *stripe.Event) (stripetotrello.EventResponse, error) {
+		return nil, fmt.Errorf("trello unavailable")
+	})
+
+	if err := client.HandleContext(context.Background(), &stripe.Event{ID: "evt_1", Type: "invoice.paid"}); err == nil {
+		t.Fatalf("Event should have failed event type = invoice.paid")
+	}
+
+	spans := recorder.Ended()
+	tcs := []testCase{
+		{"stripe.handler invoice.paid", "stripe.event invoice.paid", 0, codes.Unset},
+		{"stripe.handler invoice.paid", "stripe.event invoice.paid", 1, codes.Error},
+		{"stripe.event invoice.paid", "", -1, codes.Error},
+	}
+	if len(spans) != len(tcs) {
+		t.Fatalf("Expected %d spans, got %d", len(tcs), len(spans))
+	}
+
+	root := spans[2]
+	for i, tc := range tcs {
+		span := spans[i]
+		if span.Name() != tc.name {
+			t.Errorf("Expected span %s, got %s", tc.name, span.Name())
+		}
+		if span.Status().Code != tc.status {
+			t.Errorf("Expected span %s status %v, got %v", tc.name, tc.status, span.Status().Code)
+		}
+		if tc.parent != "" && span.Parent().SpanID() != root.SpanContext().SpanID() {
+			t.Errorf("Expected span %s to be a child of the event span", tc.name)
+		}
+
+		attrs := map[string]interface{}{}
+		for _, attr := range span.Attributes() {
+			attrs[string(attr.Key)] = attr.Value.AsInterface()
+		}
+		if attrs["stripe.event.id"] != "evt_1" || attrs["stripe.event.type"] != "invoice.paid" {
+			t.Errorf("Expected span %s to carry the event attributes, got %v", tc.name, attrs)
+		}
+		if tc.index >= 0 && attrs["stripe.handler.index"] != tc.index {
+			t.Errorf("Expected span %s handler index %d, got %v", tc.name, tc.index, attrs["stripe.handler.index"])
+		}
+	}
+}
@@ -0,0 +1,230 @@
+package trello
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/skipper-digital-studio/stripetotrello"
+	"github.com/skipper-digital-studio/stripetotrello/internal/ratelimit"
+	stripe "github.com/stripe/stripe-go/v76"
+)
+
+type (
+	// CardInput describes a card to create, Labels are label IDs of the board.
+	CardInput struct {
+		ListID      string
+		Name        string
+		Description string
+		Labels      []string
+	}
+
+	Card struct {
+		ID       string   `json:"id"`
+		Name     string   `json:"name"`
+		Desc     string   `json:"desc"`
+		IDList   string   `json:"idList"`
+		IDLabels []string `json:"idLabels"`
+		URL      string   `json:"url"`
+		ShortURL string   `json:"shortUrl"`
+	}
+)
+
+func (c *Client) CreateCard(ctx context.Context, in CardInput) (*Card, error) {
+	if in.ListID == "" {
+		return nil, NewTrelloError("trello.Client.CreateCard", []interface{}{in}, fmt.Errorf("a list id is required"))
+	}
+
+	var card Card
+	if err := c.do(ctx, http.MethodPost, "1/cards", cardParams(in), &card); err != nil {
+		return nil, NewTrelloError("trello.Client.CreateCard", []interface{}{in}, err)
+	}
+	return &card, nil
+}
+
+// UpdateCard sets the name, description and labels of the card, and moves it
+// when in has a list id.
+func (c *Client) UpdateCard(ctx context.Context, id string, in CardInput) (*Card, error) {
+	params := cardParams(in)
+	if in.ListID == "" {
+		params.Del("idList")
+	}
+
+	var card Card
+	if err := c.do(ctx, http.MethodPut, "1/cards/"+url.PathEscape(id), params, &card); err != nil {
+		return nil, NewTrelloError("trello.Client.UpdateCard", []interface{}{id, in}, err)
+	}
+	return &card, nil
+}
+
+func (c *Client) ListCards(ctx context.Context, listID string) ([]Card, error) {
+	var cards []Card
+	if err := c.do(ctx, http.MethodGet, "1/lists/"+url.PathEscape(listID)+"/cards", nil, &cards); err != nil {
+		return nil, NewTrelloError("trello.Client.ListCards", []interface{}{listID}, err)
+	}
+	return cards, nil
+}
+
+func cardParams(in CardInput) url.Values {
+	params := url.Values{}
+	params.Set("idList", in.ListID)
+	params.Set("name", in.Name)
+	params.Set("desc", in.Description)
+	if len(in.Labels) > 0 {
+		params.Set("idLabels", strings.Join(in.Labels, ","))
+	}
+	return params
+}
+
+// EVENT_MARKER prefixes the line the card handlers add to the description of
+// a card with the ID of the event it was created for.
+const EVENT_MARKER = "stripe-event: "
+
+// eventCard creates the card for the event, or updates and returns the card
+// already on the list for it when the event is delivered again. Only the
+// destination list is searched, a card moved to another list is recreated.
+// Every event lists all the cards of the list, and the search is not atomic
+// with the creation: two deliveries of the same event handled at the same
+// time can both miss the marker and create a card each.
+func (c *Client) eventCard(ctx context.Context, event *stripe.Event, in CardInput) (*Card, error) {
+	marker := EVENT_MARKER + event.ID
+	if in.Description = strings.TrimRight(in.Description, "\n"); in.Description != "" {
+		in.Description += "\n\n"
+	}
+	in.Description += marker
+
+	cards, err := c.ListCards(ctx, in.ListID)
+	if err != nil {
+		return nil, err
+	}
+	for _, card := range cards {
+		if hasMarker(card.Desc, marker) {
+			return c.UpdateCard(ctx, card.ID, in)
+		}
+	}
+	return c.CreateCard(ctx, in)
+}
+
+func hasMarker(desc, marker string) bool {
+	for _, line := range strings.Split(desc, "\n") {
+		if strings.TrimSpace(line) == marker {
+			return true
+		}
+	}
+	return false
+}
+
+// EventCardHandler returns a handler creating a card for every event it gets,
+// named after the event type and the ID of its object. A retried event
+// updates and returns the card created the first time, see EVENT_MARKER.
+// Concurrent deliveries of one event are not serialized, register the handler
+// on a client with stripetotrello.WithDeduper to only create one card. The
+// card goes on the list WithEventListMapping gives for the event type, or
+// else on listID; with neither the event is skipped and the handler returns a
+// nil response.
+func (c *Client) EventCardHandler(listID string) stripetotrello.StripeEventHandlerCtx {
+	return func(ctx context.Context, event *stripe.Event) (stripetotrello.EventResponse, error) {
+		list := c.listFor(event, listID)
+		if list == "" {
+			return nil, nil
+		}
+		return c.eventCard(ctx, event, eventInput(list, event))
+	}
+}
+
+func (c *Client) listFor(event *stripe.Event, fallback string) string {
+	if list, ok := c.eventLists[string(event.Type)]; ok && list != "" {
+		return list
+	}
+	return fallback
+}
+
+func eventInput(listID string, event *stripe.Event) CardInput {
+	name := string(event.Type)
+	if event.Data != nil {
+		if id, ok := event.Data.Object["id"].(string); ok && id != "" {
+			name = fmt.Sprintf("%s %s", event.Type, id)
+		}
+	}
+
+	return CardInput{
+		ListID:      listID,
+		Name:        name,
+		Description: fmt.Sprintf("Stripe event %s of type %s, livemode = %t", event.ID, event.Type, event.Livemode),
+	}
+}
+
+// do sends an authenticated request to the Trello API and decodes the JSON
+// response into out. It waits for the rate limiter and sends the request
+// again when Trello answers 429, up to MAX_RATE_LIMIT_RETRIES times.
+func (c *Client) do(ctx context.Context, method, path string, params url.Values, out interface{}) error {
+	base := c.baseAPIURL
+	if base == "" {
+		base = BASE_API_URL
+	}
+	httpClient := c.httpClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	query := url.Values{}
+	for k, v := range params {
+		query[k] = v
+	}
+	query.Set("key", c.apiKey)
+	query.Set("token", c.token)
+
+	req, err := http.NewRequestWithContext(ctx, method, fmt.Sprintf("%s/%s?%s", base, path, query.Encode()), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	var res *http.Response
+	for attempt := 0; ; attempt++ {
+		if c.limiter != nil {
+			if err := c.limiter.Wait(ctx); err != nil {
+				return err
+			}
+		}
+		if res, err = httpClient.Do(req); err != nil {
+			// The query carries the API key and token.
+			var urlErr *url.Error
+			if errors.As(err, &urlErr) {
+				urlErr.URL = strings.SplitN(urlErr.URL, "?", 2)[0]
+			}
+			return err
+		}
+		if res.StatusCode != http.StatusTooManyRequests || attempt == MAX_RATE_LIMIT_RETRIES {
+			break
+		}
+
+		io.Copy(io.Discard, res.Body)
+		res.Body.Close()
+		if err := ratelimit.Sleep(ctx, retryAfter(res, attempt)); err != nil {
+			return err
+		}
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(res.Body, 1<<10))
+		err := fmt.Errorf("%s %s failed - statusCode: %d - %s", method, path, res.StatusCode, strings.TrimSpace(string(body)))
+		// Trello rejects a bad request the same way every time, only rate limits
+		// and server errors are worth retrying.
+		if res.StatusCode == http.StatusTooManyRequests || res.StatusCode >= http.StatusInternalServerError {
+			return stripetotrello.MarkRetryable(err)
+		}
+		return stripetotrello.MarkPermanent(err)
+	}
+
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(res.Body).Decode(out)
+}
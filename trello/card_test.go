@@ -0,0 +1,244 @@
+package trello
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	stripe "github.com/stripe/stripe-go/v76"
+)
+
+// stubTrello records the cards created through the Trello API.
+type stubTrello struct {
+	mu    sync.Mutex
+	cards []Card
+}
+
+func (s *stubTrello) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	if q.Get("key") != "key" || q.Get("token") != "token" {
+		http.Error(w, "invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/1/cards":
+		s.mu.Lock()
+		card := Card{
+			ID:     fmt.Sprintf("card_%d", len(s.cards)+1),
+			Name:   q.Get("name"),
+			Desc:   q.Get("desc"),
+			IDList: q.Get("idList"),
+		}
+		if labels := q.Get("idLabels"); labels != "" {
+			card.IDLabels = strings.Split(labels, ",")
+		}
+		s.cards = append(s.cards, card)
+		s.mu.Unlock()
+		json.NewEncoder(w).Encode(card)
+	case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/1/lists/") && strings.HasSuffix(r.URL.Path, "/cards"):
+		list := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/1/lists/"), "/cards")
+		cards := []Card{}
+		for _, card := range s.Cards() {
+			if card.IDList == list {
+				cards = append(cards, card)
+			}
+		}
+		json.NewEncoder(w).Encode(cards)
+	case r.Method == http.MethodPut && strings.HasPrefix(r.URL.Path, "/1/cards/"):
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		for i, card := range s.cards {
+			if card.ID != strings.TrimPrefix(r.URL.Path, "/1/cards/") {
+				continue
+			}
+			card.Name, card.Desc = q.Get("name"), q.Get("desc")
+			if list := q.Get("idList"); list != "" {
+				card.IDList = list
+			}
+			s.cards[i] = card
+			json.NewEncoder(w).Encode(card)
+			return
+		}
+		http.NotFound(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (s *stubTrello) Cards() []Card {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Card(nil), s.cards...)
+}
+
+func newStub(t *testing.T, options ...func(*Client)) (*stubTrello, *Client) {
+	stub := &stubTrello{}
+	srv := httptest.NewServer(stub)
+	t.Cleanup(srv.Close)
+	return stub, NewTrello("key", "token", append([]func(*Client){WithBaseAPIURL(srv.URL)}, options...)...)
+}
+
+func TestCreateCard(t *testing.T) {
+	type testCase struct {
+		input      CardInput
+		shouldFail bool
+	}
+
+	tcs := []testCase{
+		{CardInput{ListID: "list_1", Name: "New customer", Description: "A & B\nsecond line"}, false},
+		{CardInput{ListID: "list_1", Name: "Labelled", Labels: []string{"lbl_1", "lbl_2"}}, false},
+		{CardInput{Name: "No list"}, true},
+	}
+
+	stub, client := newStub(t)
+	for _, tc := range tcs {
+		card, err := client.CreateCard(context.Background(), tc.input)
+		if err != nil && !tc.shouldFail {
+			t.Errorf("Card should have NOT failed %+v - %v", tc.input, err)
+			continue
+		}
+		if err == nil && tc.shouldFail {
+			t.Errorf("Card should have failed %+v", tc.input)
+		}
+		if err != nil {
+			continue
+		}
+		if card.Name != tc.input.Name || card.Desc != tc.input.Description || card.IDList != tc.input.ListID {
+			t.Errorf("Expected card %+v got %+v", tc.input, card)
+		}
+		if strings.Join(card.IDLabels, ",") != strings.Join(tc.input.Labels, ",") {
+			t.Errorf("Expected labels %v got %v", tc.input.Labels, card.IDLabels)
+		}
+	}
+
+	if got := len(stub.Cards()); got != 2 {
+		t.Errorf("Expected 2 cards got %d", got)
+	}
+}
+
+func TestCreateCardUnauthorized(t *testing.T) {
+	_, client := newStub(t, WithToken("wrong"))
+	if _, err := client.CreateCard(context.Background(), CardInput{ListID: "list_1", Name: "card"}); err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("Expected a 401 error got %v", err)
+	}
+}
+
+func TestCreateCardTransportError(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	srv.Close()
+
+	client := NewTrello("key", "SECRET", WithBaseAPIURL(srv.URL))
+	_, err := client.CreateCard(context.Background(), CardInput{ListID: "list_1", Name: "card"})
+	if err == nil || strings.Contains(err.Error(), "SECRET") {
+		t.Errorf("Expected a transport error without the token, got %v", err)
+	}
+}
+
+func TestEventCardHandler(t *testing.T) {
+	stub, client := newStub(t)
+	handler := client.EventCardHandler("list_1")
+
+	event := &stripe.Event{
+		ID:   "evt_1",
+		Type: "customer.created",
+		Data: &stripe.EventData{Object: map[string]interface{}{"id": "cus_1"}},
+	}
+	res, err := handler(context.Background(), event)
+	if err != nil {
+		t.Fatalf("Event should have NOT failed %v", err)
+	}
+
+	card, ok := res.(*Card)
+	if !ok {
+		t.Fatalf("Expected a *Card response got %T", res)
+	}
+	if card.Name != "customer.created cus_1" || card.IDList != "list_1" || !strings.Contains(card.Desc, "evt_1") {
+		t.Errorf("Expected the card to describe the event got %+v", card)
+	}
+	if got := len(stub.Cards()); got != 1 {
+		t.Errorf("Expected 1 card got %d", got)
+	}
+}
+
+func TestEventListMapping(t *testing.T) {
+	type testCase struct {
+		eventType stripe.EventType
+		fallback  string
+		list      string
+	}
+
+	mapping := WithEventListMapping(map[string]string{
+		"invoice.payment_failed":        "dunning",
+		"customer.subscription.deleted": "churn",
+	})
+
+	tcs := []testCase{
+		{"invoice.payment_failed", "default", "dunning"},
+		{"customer.subscription.deleted", "default", "churn"},
+		{"customer.created", "default", "default"},
+		{"customer.created", "", ""},
+	}
+
+	for _, tc := range tcs {
+		stub, client := newStub(t, mapping)
+		res, err := client.EventCardHandler(tc.fallback)(context.Background(), &stripe.Event{ID: "evt_1", Type: tc.eventType})
+		if err != nil {
+			t.Fatalf("Event should have NOT failed %s - %v", tc.eventType, err)
+		}
+
+		cards := stub.Cards()
+		if tc.list == "" {
+			if len(cards) != 0 || res != nil {
+				t.Errorf("Expected %s to be skipped got %+v", tc.eventType, cards)
+			}
+			continue
+		}
+		if len(cards) != 1 || cards[0].IDList != tc.list {
+			t.Errorf("Expected %s on list %s got %+v", tc.eventType, tc.list, cards)
+		}
+	}
+}
+
+func TestEventCardHandlerIdempotent(t *testing.T) {
+	stub, client := newStub(t)
+	handler := client.EventCardHandler("list_1")
+
+	var ids []string
+	for _, id := range []string{"cus_1", "cus_1_renamed"} {
+		event := &stripe.Event{
+			ID:   "evt_1",
+			Type: "customer.created",
+			Data: &stripe.EventData{Object: map[string]interface{}{"id": id}},
+		}
+		res, err := handler(context.Background(), event)
+		if err != nil {
+			t.Fatalf("Event should have NOT failed %v", err)
+		}
+		ids = append(ids, res.(*Card).ID)
+	}
+
+	cards := stub.Cards()
+	if len(cards) != 1 {
+		t.Fatalf("Expected 1 card got %+v", cards)
+	}
+	if ids[0] != ids[1] {
+		t.Errorf("Expected the existing card to be returned got %v", ids)
+	}
+	if cards[0].Name != "customer.created cus_1_renamed" || !hasMarker(cards[0].Desc, EVENT_MARKER+"evt_1") {
+		t.Errorf("Expected the card to be updated got %+v", cards[0])
+	}
+
+	other := &stripe.Event{ID: "evt_2", Type: "customer.created"}
+	if _, err := handler(context.Background(), other); err != nil {
+		t.Fatalf("Event should have NOT failed %v", err)
+	}
+	if got := len(stub.Cards()); got != 2 {
+		t.Errorf("Expected another event to get its own card got %d cards", got)
+	}
+}
@@ -19,6 +19,10 @@ func (t TrelloError) Error() string {
 	return fmt.Sprintf("Error calling %s - with args %v - result in error %s", t.fn, t.args, t.err.Error())
 }
 
+func (t TrelloError) Unwrap() error {
+	return t.err
+}
+
 func NewTrelloError(fn string, args []interface{}, err error) TrelloError {
 	return TrelloError{
 		fn,
@@ -0,0 +1,49 @@
+package trello
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/skipper-digital-studio/stripetotrello/internal/ratelimit"
+)
+
+const (
+	// MAX_RATE_LIMIT_RETRIES is how many times a request answered with 429 is
+	// sent again before the error is returned.
+	MAX_RATE_LIMIT_RETRIES = 3
+	RATE_LIMIT_BACKOFF     = time.Second
+)
+
+// WithTrelloRateLimit lets the client send at most rps requests per second
+// with bursts of up to burst requests, Trello allows 100 requests per 10
+// seconds per token. Requests wait for their turn until their context is done.
+func WithTrelloRateLimit(rps float64, burst int) func(*Client) {
+	return func(c *Client) {
+		if rps <= 0 {
+			return
+		}
+		if burst < 1 {
+			burst = 1
+		}
+		c.limiter = ratelimit.New(rps, float64(burst))
+	}
+}
+
+// retryAfter is how long to wait before sending again a request answered
+// with 429, from its Retry-After header in seconds or as a date, doubling
+// RATE_LIMIT_BACKOFF on each attempt without one.
+func retryAfter(res *http.Response, attempt int) time.Duration {
+	if v := res.Header.Get("Retry-After"); v != "" {
+		if seconds, err := strconv.Atoi(v); err == nil && seconds >= 0 {
+			return time.Duration(seconds) * time.Second
+		}
+		if at, err := http.ParseTime(v); err == nil {
+			if d := time.Until(at); d > 0 {
+				return d
+			}
+			return 0
+		}
+	}
+	return RATE_LIMIT_BACKOFF << attempt
+}
@@ -0,0 +1,80 @@
+package trello
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/skipper-digital-studio/stripetotrello"
+)
+
+func TestRetryOnTooManyRequests(t *testing.T) {
+	stub := &stubTrello{}
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if hits.Add(1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			http.Error(w, "API_TOKEN_LIMIT_EXCEEDED", http.StatusTooManyRequests)
+			return
+		}
+		stub.ServeHTTP(w, r)
+	}))
+	defer srv.Close()
+
+	client := NewTrello("key", "token", WithBaseAPIURL(srv.URL))
+	start := time.Now()
+	card, err := client.CreateCard(context.Background(), CardInput{ListID: "list_1", Name: "card"})
+	if err != nil {
+		t.Fatalf("Card should have NOT failed %v", err)
+	}
+
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("Expected the retry to wait for Retry-After got %s", elapsed)
+	}
+	if got := hits.Load(); got != 2 {
+		t.Errorf("Expected 2 requests got %d", got)
+	}
+	if cards := stub.Cards(); len(cards) != 1 || cards[0].ID != card.ID {
+		t.Errorf("Expected a single card got %+v", cards)
+	}
+}
+
+func TestRetryOnTooManyRequestsGivesUp(t *testing.T) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.Header().Set("Retry-After", "0")
+		http.Error(w, "API_TOKEN_LIMIT_EXCEEDED", http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	client := NewTrello("key", "token", WithBaseAPIURL(srv.URL))
+	_, err := client.CreateCard(context.Background(), CardInput{ListID: "list_1", Name: "card"})
+	if err == nil {
+		t.Errorf("Card should have failed")
+	}
+	if !stripetotrello.IsRetryable(err) {
+		t.Errorf("Expected a rate limited card to be retryable, got %v", err)
+	}
+	if got := hits.Load(); got != MAX_RATE_LIMIT_RETRIES+1 {
+		t.Errorf("Expected %d requests got %d", MAX_RATE_LIMIT_RETRIES+1, got)
+	}
+}
+
+func TestTrelloRateLimit(t *testing.T) {
+	_, client := newStub(t, WithTrelloRateLimit(20, 1))
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if _, err := client.CreateCard(context.Background(), CardInput{ListID: "list_1", Name: "card"}); err != nil {
+			t.Fatalf("Card should have NOT failed %v", err)
+		}
+	}
+	// The first request uses the burst, the other two wait 50ms each.
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Errorf("Expected the requests to be rate limited got %s", elapsed)
+	}
+}
@@ -0,0 +1,192 @@
+package trello
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"text/template"
+	"text/template/parse"
+
+	"github.com/skipper-digital-studio/stripetotrello"
+	stripe "github.com/stripe/stripe-go/v76"
+)
+
+// CardTemplate holds text/template sources rendered against the event data
+// object, e.g. "Payment failed: {{.customer_email}}".
+type CardTemplate struct {
+	TitleTmpl string
+	BodyTmpl  string
+}
+
+type cardTemplate struct {
+	title *template.Template
+	body  *template.Template
+}
+
+func (tmpl CardTemplate) parse() (cardTemplate, error) {
+	if tmpl.TitleTmpl == "" {
+		return cardTemplate{}, fmt.Errorf("a title template is required")
+	}
+
+	title, err := template.New("title").Option("missingkey=zero").Parse(tmpl.TitleTmpl)
+	if err != nil {
+		return cardTemplate{}, err
+	}
+	body, err := template.New("body").Option("missingkey=zero").Parse(tmpl.BodyTmpl)
+	if err != nil {
+		return cardTemplate{}, err
+	}
+	return cardTemplate{title, body}, nil
+}
+
+// CardHandler returns a handler creating a card with its content rendered
+// from tmpl, on the list picked like EventCardHandler does with list as the
+// fallback, and deduplicated the same way. Fields missing from the event
+// render empty, a template that does not parse is reported here rather than
+// when an event comes in. Only the fields of the data object itself are
+// filled in, not the ones referenced relative to a range or with block.
+func (c *Client) CardHandler(list string, tmpl CardTemplate) (stripetotrello.StripeEventHandlerCtx, error) {
+	parsed, err := tmpl.parse()
+	if err != nil {
+		return nil, NewTrelloError("trello.Client.CardHandler", []interface{}{list, tmpl}, err)
+	}
+
+	return func(ctx context.Context, event *stripe.Event) (stripetotrello.EventResponse, error) {
+		dest := c.listFor(event, list)
+		if dest == "" {
+			return nil, nil
+		}
+		in, err := parsed.render(dest, event)
+		if err != nil {
+			return nil, NewTrelloError("trello.Client.CardHandler", []interface{}{event}, err)
+		}
+		return c.eventCard(ctx, event, in)
+	}, nil
+}
+
+func (t cardTemplate) render(list string, event *stripe.Event) (CardInput, error) {
+	var object map[string]interface{}
+	if event.Data != nil {
+		object = event.Data.Object
+	}
+
+	title, err := execute(t.title, object)
+	if err != nil {
+		return CardInput{}, err
+	}
+	body, err := execute(t.body, object)
+	if err != nil {
+		return CardInput{}, err
+	}
+	return CardInput{ListID: list, Name: title, Description: body}, nil
+}
+
+func execute(tmpl *template.Template, object map[string]interface{}) (string, error) {
+	data := fill(object, fieldPaths(tmpl.Tree.Root))
+
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// fill returns a copy of object where every missing field in paths is set,
+// to an empty map along the way and an empty string at the end, so the
+// template neither prints "<no value>" nor fails on a nil parent.
+func fill(object map[string]interface{}, paths [][]string) map[string]interface{} {
+	data := copyObject(object)
+	for _, path := range paths {
+		m := data
+		for i, key := range path {
+			v, ok := m[key]
+			if i == len(path)-1 {
+				if !ok || v == nil {
+					m[key] = ""
+				}
+				break
+			}
+
+			switch child := v.(type) {
+			case map[string]interface{}:
+				m = child
+			case nil:
+				next := map[string]interface{}{}
+				m[key] = next
+				m = next
+			default:
+				// A value that is not an object is left alone, the template
+				// decides what to make of it.
+				m = nil
+			}
+			if m == nil {
+				break
+			}
+		}
+	}
+	return data
+}
+
+// copyObject copies the nested maps fill may write to, the event is shared
+// with the other handlers.
+func copyObject(object map[string]interface{}) map[string]interface{} {
+	output := make(map[string]interface{}, len(object))
+	for k, v := range object {
+		if m, ok := v.(map[string]interface{}); ok {
+			v = copyObject(m)
+		}
+		output[k] = v
+	}
+	return output
+}
+
+// fieldPaths lists the fields the template reads from its data, either as
+// .a.b or $.a.b, skipping the bodies of range and with where dot changes.
+func fieldPaths(node parse.Node) [][]string {
+	var paths [][]string
+	var walk func(parse.Node)
+	walk = func(node parse.Node) {
+		switch n := node.(type) {
+		case *parse.ListNode:
+			if n == nil {
+				return
+			}
+			for _, child := range n.Nodes {
+				walk(child)
+			}
+		case *parse.ActionNode:
+			walk(n.Pipe)
+		case *parse.PipeNode:
+			if n == nil {
+				return
+			}
+			for _, cmd := range n.Cmds {
+				walk(cmd)
+			}
+		case *parse.CommandNode:
+			for _, arg := range n.Args {
+				walk(arg)
+			}
+		case *parse.FieldNode:
+			paths = append(paths, n.Ident)
+		case *parse.VariableNode:
+			if len(n.Ident) > 1 && n.Ident[0] == "$" {
+				paths = append(paths, n.Ident[1:])
+			}
+		case *parse.ChainNode:
+			walk(n.Node)
+		case *parse.IfNode:
+			walk(n.Pipe)
+			walk(n.List)
+			walk(n.ElseList)
+		case *parse.RangeNode:
+			walk(n.Pipe)
+			walk(n.ElseList)
+		case *parse.WithNode:
+			walk(n.Pipe)
+			walk(n.ElseList)
+		}
+	}
+	walk(node)
+	return paths
+}
@@ -0,0 +1,112 @@
+package trello
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	stripe "github.com/stripe/stripe-go/v76"
+)
+
+func invoicePaymentFailed(t *testing.T) *stripe.Event {
+	t.Helper()
+	payload := []byte(`{
+		"id": "evt_1",
+		"type": "invoice.payment_failed",
+		"data": {"object": {
+			"id": "in_1",
+			"object": "invoice",
+			"number": "INV-0001",
+			"amount_due": 2000,
+			"currency": "usd",
+			"customer_email": "jenny@example.com",
+			"customer": "cus_1"
+		}}
+	}`)
+
+	var event stripe.Event
+	if err := json.Unmarshal(payload, &event); err != nil {
+		t.Fatalf("Failed to parse the event %v", err)
+	}
+	return &event
+}
+
+func TestCardTemplate(t *testing.T) {
+	type testCase struct {
+		tmpl  CardTemplate
+		title string
+		body  string
+	}
+
+	tcs := []testCase{
+		{
+			CardTemplate{"Payment failed: {{.customer_email}}", "Invoice {{.number}} for {{.amount_due}} {{.currency}}"},
+			"Payment failed: jenny@example.com",
+			"Invoice INV-0001 for 2000 usd",
+		},
+		{
+			CardTemplate{"{{.number}}{{.missing}}", "{{.subscription_details.metadata.plan}}|{{$.account_name}}"},
+			"INV-0001",
+			"|",
+		},
+		{
+			CardTemplate{"{{if .paid}}paid{{else}}unpaid{{end}} {{.id}}", ""},
+			"unpaid in_1",
+			"",
+		},
+	}
+
+	event := invoicePaymentFailed(t)
+	for _, tc := range tcs {
+		parsed, err := tc.tmpl.parse()
+		if err != nil {
+			t.Fatalf("Template should have NOT failed %+v - %v", tc.tmpl, err)
+		}
+		in, err := parsed.render("list_1", event)
+		if err != nil {
+			t.Fatalf("Render should have NOT failed %+v - %v", tc.tmpl, err)
+		}
+		if in.Name != tc.title || in.Description != tc.body || in.ListID != "list_1" {
+			t.Errorf("Expected %q / %q got %q / %q", tc.title, tc.body, in.Name, in.Description)
+		}
+	}
+
+	if _, ok := event.Data.Object["missing"]; ok {
+		t.Errorf("Expected rendering to leave the event untouched")
+	}
+}
+
+func TestCardHandlerMalformedTemplate(t *testing.T) {
+	tcs := []CardTemplate{
+		{"{{.number", ""},
+		{"title", "{{end}}"},
+		{"", "body"},
+	}
+
+	client := NewTrello("key", "token")
+	for _, tmpl := range tcs {
+		if _, err := client.CardHandler("list_1", tmpl); err == nil {
+			t.Errorf("Expected an error at registration for %+v", tmpl)
+		}
+	}
+}
+
+func TestCardHandler(t *testing.T) {
+	stub, client := newStub(t)
+	handler, err := client.CardHandler("list_1", CardTemplate{
+		TitleTmpl: "Payment failed: {{.customer_email}}",
+		BodyTmpl:  "Invoice {{.number}}",
+	})
+	if err != nil {
+		t.Fatalf("Template should have NOT failed %v", err)
+	}
+
+	if _, err := handler(context.Background(), invoicePaymentFailed(t)); err != nil {
+		t.Fatalf("Event should have NOT failed %v", err)
+	}
+
+	cards := stub.Cards()
+	if len(cards) != 1 || cards[0].Name != "Payment failed: jenny@example.com" || cards[0].Desc != "Invoice INV-0001\n\n"+EVENT_MARKER+"evt_1" {
+		t.Errorf("Expected the rendered card got %+v", cards)
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+
+	"github.com/skipper-digital-studio/stripetotrello/internal/ratelimit"
 )
 
 const (
@@ -29,6 +31,10 @@ type (
 		appName        string
 		organizationID string
 		scopes         []string
+		baseAPIURL     string
+		httpClient     *http.Client
+		eventLists     map[string]string
+		limiter        *ratelimit.Bucket
 	}
 )
 
@@ -48,13 +54,48 @@ func (t StatusEnum) GetValue() (string, error) {
 }
 
 func NewClient(options ...func(*Client)) *Client {
-	c := &Client{}
+	c := &Client{
+		baseAPIURL: BASE_API_URL,
+		httpClient: http.DefaultClient,
+	}
 	for _, f := range options {
 		f(c)
 	}
 	return c
 }
 
+// NewTrello returns a client authenticated with an API key and token, the
+// minimal setup CreateCard needs.
+func NewTrello(key, token string, options ...func(*Client)) *Client {
+	return NewClient(append([]func(*Client){WithAPIKey(key), WithToken(token)}, options...)...)
+}
+
+// WithBaseAPIURL overrides BASE_API_URL for the requests made by CreateCard,
+// e.g. to point the client at a stub in tests.
+func WithBaseAPIURL(url string) func(*Client) {
+	return func(c *Client) {
+		c.baseAPIURL = strings.TrimSuffix(url, "/")
+	}
+}
+
+// WithEventListMapping sets the list the card handlers put the cards of each
+// event type on, e.g. "invoice.payment_failed" to the ID of a Dunning list.
+// Other event types go to the list given to the handler, when there is one.
+func WithEventListMapping(m map[string]string) func(*Client) {
+	return func(c *Client) {
+		c.eventLists = make(map[string]string, len(m))
+		for eventType, list := range m {
+			c.eventLists[eventType] = list
+		}
+	}
+}
+
+func WithHTTPClient(httpClient *http.Client) func(*Client) {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
 func WithOrganizationID(id string) func(*Client) {
 	return func(c *Client) {
 		c.organizationID = id
@@ -0,0 +1,38 @@
+package stripetotrello
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	stripe "github.com/stripe/stripe-go/v76"
+)
+
+// RegisterTyped registers fn for eventType, decoding the event data into a T
+// before calling it, e.g. RegisterTyped[stripe.Invoice](c, "invoice.paid", fn).
+// The object comes from DecodedObject, see WithDecodedType to decode it once
+// for all the handlers.
+func RegisterTyped[T any](c *Client, eventType string, fn func(ctx context.Context, obj *T) (EventResponse, error)) {
+	h := func(ctx context.Context, event *stripe.Event) (EventResponse, error) {
+		obj, err := DecodedObject[T](ctx, event)
+		if err != nil {
+			return nil, err
+		}
+		return fn(ctx, obj)
+	}
+	// Dedupe on fn rather than on the decoding closure every call shares.
+	c.logRegistration(c.appendHandlers(eventType, 0, nil, registeredHandler{fn: h, id: reflect.ValueOf(fn).Pointer()}))
+}
+
+func UnmarshalEventObject[T any](event *stripe.Event) (*T, error) {
+	if event.Data == nil {
+		return nil, newError("stripetotrello.UnmarshalEventObject", []interface{}{event}, fmt.Errorf("event has no data"))
+	}
+
+	var obj T
+	if err := json.Unmarshal(event.Data.Raw, &obj); err != nil {
+		return nil, newError("stripetotrello.UnmarshalEventObject", []interface{}{event}, err)
+	}
+	return &obj, nil
+}
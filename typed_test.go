@@ -0,0 +1,83 @@
+package stripetotrello
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	stripe "github.com/stripe/stripe-go/v76"
+)
+
+func TestRegisterTyped(t *testing.T) {
+	type testCase struct {
+		event      stripe.Event
+		expected   EventResponse
+		shouldFail bool
+	}
+
+	client := NewClient()
+	RegisterTyped(client, "invoice.payment_failed", func(_ context.Context, invoice *stripe.Invoice) (EventResponse, error) {
+		return invoice.ID, nil
+	})
+	RegisterTyped(client, "customer.subscription.updated", func(_ context.Context, sub *stripe.Subscription) (EventResponse, error) {
+		return string(sub.Status), nil
+	})
+
+	tcs := []testCase{
+		{stripe.Event{Type: "invoice.payment_failed", Data: &stripe.EventData{Raw: json.RawMessage(`{"id": "in_1", "object": "invoice"}`)}}, "in_1", false},
+		{stripe.Event{Type: "customer.subscription.updated", Data: &stripe.EventData{Raw: json.RawMessage(`{"id": "sub_1", "status": "canceled"}`)}}, "canceled", false},
+		{stripe.Event{Type: "invoice.payment_failed", Data: &stripe.EventData{Raw: json.RawMessage(`{"id": 1`)}}, nil, true},
+		{stripe.Event{Type: "invoice.payment_failed"}, nil, true},
+	}
+
+	for _, tc := range tcs {
+		res, err := client.HandleCollect(&tc.event)
+		if err != nil && !tc.shouldFail {
+			t.Errorf("Event should have NOT failed event type = %s, got %s", tc.event.Type, err)
+		}
+
+		if err == nil && tc.shouldFail {
+			t.Errorf("Event should have failed event type = %s", tc.event.Type)
+		}
+
+		if err == nil && (len(res) != 1 || res[0] != tc.expected) {
+			t.Errorf("Expected result %v for event type = %s, got %v", tc.expected, tc.event.Type, res)
+		}
+
+		if _, ok := err.(StripeEventError); err != nil && !ok {
+			t.Errorf("Expected a StripeEventError for event type = %s, got %T", tc.event.Type, err)
+		}
+	}
+}
+
+func TestUnmarshalEventObject(t *testing.T) {
+	type testCase struct {
+		event      stripe.Event
+		id         string
+		shouldFail bool
+	}
+
+	tcs := []testCase{
+		{stripe.Event{Data: &stripe.EventData{Raw: json.RawMessage(`{"id": "cus_1", "email": "jane@example.com"}`)}}, "cus_1", false},
+		{stripe.Event{Data: &stripe.EventData{Raw: json.RawMessage(`{"id": "cus_2",`)}}, "", true},
+		{stripe.Event{}, "", true},
+	}
+
+	for _, tc := range tcs {
+		customer, err := UnmarshalEventObject[stripe.Customer](&tc.event)
+		if err != nil && !tc.shouldFail {
+			t.Errorf("Expected customer %s to decode, got %s", tc.id, err)
+		}
+		if err == nil && tc.shouldFail {
+			t.Errorf("Expected decoding to fail")
+		}
+		if err == nil && customer.ID != tc.id {
+			t.Errorf("Expected customer id %s, got %s", tc.id, customer.ID)
+		}
+	}
+
+	charge, err := UnmarshalEventObject[stripe.Charge](&stripe.Event{Data: &stripe.EventData{Raw: json.RawMessage(`{"id": "ch_1", "amount": 1500}`)}})
+	if err != nil || charge.Amount != 1500 {
+		t.Errorf("Expected charge amount 1500, got %v - %v", charge, err)
+	}
+}
@@ -0,0 +1,112 @@
+package stripetotrello
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	stripe "github.com/stripe/stripe-go/v76"
+	"github.com/stripe/stripe-go/v76/webhook"
+)
+
+type (
+	// Verifier checks the signature of a payload and decodes it. Errors that
+	// do not wrap ErrSignatureVerification or ErrPayloadParse are reported by
+	// Event as ErrPayloadParse, except the webhook package ones.
+	Verifier interface {
+		Verify(raw []byte, signature string) (*stripe.Event, error)
+	}
+
+	// WebhookVerifier is the default Verifier, built from the client options.
+	// It accepts payloads signed with any of Secrets, the signature timestamp
+	// is checked against Clock, the wall clock when nil.
+	WebhookVerifier struct {
+		Secrets []string
+		Options webhook.ConstructEventOptions
+		Clock   Clock
+	}
+
+	insecureVerifier struct{}
+)
+
+// WithVerifier replaces the signature verification done by Event, e.g. when
+// it already happened upstream. It takes precedence over the secret options
+// and WithInsecureSkipVerify.
+func WithVerifier(v Verifier) func(*Client) {
+	return func(c *Client) {
+		c.verifier = v
+	}
+}
+
+func (st *Client) defaultVerifier(secrets []string) Verifier {
+	if st.insecureSkipVerify && len(secrets) == 0 {
+		return insecureVerifier{}
+	}
+	return &WebhookVerifier{
+		Secrets: secrets,
+		Options: webhook.ConstructEventOptions{
+			Tolerance:                st.tolerance,
+			IgnoreAPIVersionMismatch: st.ignoreAPIVersion,
+		},
+		Clock: st.clock,
+	}
+}
+
+func (v *WebhookVerifier) Verify(raw []byte, signature string) (*stripe.Event, error) {
+	secrets := v.Secrets
+	if len(secrets) == 0 {
+		secrets = []string{""}
+	}
+
+	// The tolerance is checked here rather than by the webhook package, which
+	// always uses the wall clock.
+	opts := v.Options
+	opts.IgnoreTolerance = true
+
+	var err error
+	for _, secret := range secrets {
+		var event stripe.Event
+		event, err = webhook.ConstructEventWithOptions(raw, signature, secret, opts)
+		if err == nil {
+			if !v.Options.IgnoreTolerance && v.expired(signature) {
+				return nil, webhook.ErrTooOld
+			}
+			return &event, nil
+		}
+		// Only a signature mismatch depends on the secret, anything else fails
+		// the same way for all of them.
+		if err != webhook.ErrNoValidSignature {
+			break
+		}
+	}
+	return nil, err
+}
+
+func (v *WebhookVerifier) expired(signature string) bool {
+	tolerance := v.Options.Tolerance
+	if tolerance == 0 {
+		tolerance = webhook.DefaultTolerance
+	}
+	clock := v.Clock
+	if clock == nil {
+		clock = realClock{}
+	}
+
+	for _, pair := range strings.Split(signature, ",") {
+		if ts, ok := strings.CutPrefix(pair, "t="); ok {
+			unix, err := strconv.ParseInt(ts, 10, 64)
+			return err != nil || clock.Now().Sub(time.Unix(unix, 0)) > tolerance
+		}
+	}
+	return true
+}
+
+func (insecureVerifier) Verify(raw []byte, _ string) (*stripe.Event, error) {
+	var event stripe.Event
+	if err := json.Unmarshal(raw, &event); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrPayloadParse, err)
+	}
+	return &event, nil
+}
@@ -0,0 +1,56 @@
+package stripetotrello
+
+import (
+	"errors"
+	"testing"
+
+	stripe "github.com/stripe/stripe-go/v76"
+)
+
+type mockVerifier struct {
+	raw       []byte
+	signature string
+	event     *stripe.Event
+	err       error
+}
+
+func (m *mockVerifier) Verify(raw []byte, signature string) (*stripe.Event, error) {
+	m.raw, m.signature = raw, signature
+	return m.event, m.err
+}
+
+func TestVerifier(t *testing.T) {
+	type testCase struct {
+		name     string
+		verifier *mockVerifier
+		sentinel error
+	}
+
+	tcs := []testCase{
+		{"canned event", &mockVerifier{event: &stripe.Event{ID: "evt_mock", Type: "customer.created"}}, nil},
+		{"signature error", &mockVerifier{err: ErrSignatureVerification}, ErrSignatureVerification},
+		{"other error", &mockVerifier{err: errors.New("gateway said no")}, ErrPayloadParse},
+	}
+
+	for _, tc := range tcs {
+		client := NewClient(WithStripeWebhookSecret(testSecret), WithVerifier(tc.verifier))
+
+		event, err := client.Event([]byte("payload"), "t=1,v1=abc")
+		if string(tc.verifier.raw) != "payload" || tc.verifier.signature != "t=1,v1=abc" {
+			t.Errorf("Expected %s to pass the raw payload and signature to the verifier, got %q and %q", tc.name, tc.verifier.raw, tc.verifier.signature)
+		}
+		if tc.sentinel != nil {
+			if !errors.Is(err, tc.sentinel) {
+				t.Errorf("Expected %s to fail with %s, got %v", tc.name, tc.sentinel, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Expected %s to NOT fail, got %s", tc.name, err)
+			continue
+		}
+		if event != tc.verifier.event {
+			t.Errorf("Expected %s to return the canned event, got %v", tc.name, event)
+		}
+	}
+}